@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/coeeter/zap/internal/cache"
+	"github.com/spf13/cobra"
+)
+
+func newCacheCmd() *cobra.Command {
+	cacheCmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Manage the scan index",
+	}
+
+	cacheCmd.AddCommand(&cobra.Command{
+		Use:   "clear",
+		Short: "Delete the scan index so the next scan starts fresh",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir, err := cache.Dir()
+			if err != nil {
+				return err
+			}
+			if err := cache.Clear(); err != nil {
+				return err
+			}
+			fmt.Printf("Cleared %s\n", dir)
+			return nil
+		},
+	})
+
+	return cacheCmd
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ageUnits = map[string]time.Duration{
+	"m":  time.Minute,
+	"h":  time.Hour,
+	"d":  24 * time.Hour,
+	"w":  7 * 24 * time.Hour,
+	"mo": 30 * 24 * time.Hour,
+	"y":  365 * 24 * time.Hour,
+}
+
+func parseAge(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	i := strings.IndexFunc(s, func(r rune) bool { return (r < '0' || r > '9') && r != '.' })
+	if i <= 0 {
+		return 0, fmt.Errorf("invalid age %q (want a number and a unit, e.g. 60d)", s)
+	}
+
+	n, err := strconv.ParseFloat(s[:i], 64)
+	unit, ok := ageUnits[strings.ToLower(s[i:])]
+	if err != nil || !ok {
+		return 0, fmt.Errorf("invalid age %q (want a number followed by m, h, d, w, mo or y)", s)
+	}
+	return time.Duration(n * float64(unit)), nil
+}
+
+var sizeUnits = map[string]float64{
+	"":  1,
+	"k": 1 << 10,
+	"m": 1 << 20,
+	"g": 1 << 30,
+	"t": 1 << 40,
+}
+
+func parseSize(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	i := strings.IndexFunc(s, func(r rune) bool { return (r < '0' || r > '9') && r != '.' })
+	if i == 0 {
+		return 0, fmt.Errorf("invalid size %q (want a number and a unit, e.g. 20G)", s)
+	}
+	if i < 0 {
+		i = len(s)
+	}
+
+	n, err := strconv.ParseFloat(s[:i], 64)
+	unit := strings.TrimSpace(strings.ToLower(s[i:]))
+	unit = strings.TrimSuffix(strings.TrimSuffix(unit, "b"), "i")
+	mult, ok := sizeUnits[unit]
+	if err != nil || !ok {
+		return 0, fmt.Errorf("invalid size %q (want a number followed by K, M, G or T)", s)
+	}
+	return int64(n * mult), nil
+}
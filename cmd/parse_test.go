@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseAge(t *testing.T) {
+	day := 24 * time.Hour
+	tests := []struct {
+		in   string
+		want time.Duration
+	}{
+		{"60d", 60 * day},
+		{"5m", 5 * time.Minute},
+		{"6mo", 180 * day},
+		{"6MO", 180 * day},
+		{"12w", 84 * day},
+		{"1y", 365 * day},
+		{"1.5h", 90 * time.Minute},
+		{" 2d ", 2 * day},
+	}
+	for _, tt := range tests {
+		got, err := parseAge(tt.in)
+		if err != nil {
+			t.Errorf("parseAge(%q): %v", tt.in, err)
+		} else if got != tt.want {
+			t.Errorf("parseAge(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+
+	for _, in := range []string{"", "60", "d", "-5d", "5x", "5mos", "1.2.3d"} {
+		if _, err := parseAge(in); err == nil {
+			t.Errorf("parseAge(%q): expected an error", in)
+		}
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int64
+	}{
+		{"100", 100},
+		{"2k", 2 << 10},
+		{"500M", 500 << 20},
+		{"10 MB", 10 << 20},
+		{"1G", 1 << 30},
+		{"1.5GiB", 3 << 29},
+		{"1.5gb", 3 << 29},
+		{"2T", 2 << 40},
+	}
+	for _, tt := range tests {
+		got, err := parseSize(tt.in)
+		if err != nil {
+			t.Errorf("parseSize(%q): %v", tt.in, err)
+		} else if got != tt.want {
+			t.Errorf("parseSize(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+
+	for _, in := range []string{"", "G", "-5G", "5X", "5GG", "1.2.3G"} {
+		if _, err := parseSize(in); err == nil {
+			t.Errorf("parseSize(%q): expected an error", in)
+		}
+	}
+}
@@ -4,63 +4,179 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 
+	"github.com/coeeter/zap/internal/config"
+	"github.com/coeeter/zap/internal/history"
 	"github.com/coeeter/zap/internal/scan"
 	"github.com/coeeter/zap/internal/tui"
 	"github.com/spf13/cobra"
 )
 
-var searchMode bool
+var (
+	searchMode   bool
+	regexMode    bool
+	entryType    string
+	olderThan    string
+	minSize      string
+	freeTarget   string
+	freeBy       string
+	noCache      bool
+	colorMode    string
+	themeName    string
+	previewDepth int
+	previewItems int
+	presets      []string
+	rootPaths    []string
+	walkOpts     scan.Options
+)
 
 func Execute() error {
 	rootCmd := &cobra.Command{
-		Use:   "zap [folder-name]",
+		Use:   "zap [folder-name] [root...]",
 		Short: "A fast way to search and remove folders",
-		Args:  cobra.MaximumNArgs(1),
+		Args:  cobra.ArbitraryArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			var targetFolder string
+			cfg, err := config.Load()
+			if err != nil {
+				return err
+			}
+
+			if !cmd.Flags().Changed("color") && cfg.Color != "" {
+				colorMode = cfg.Color
+			}
+			if !cmd.Flags().Changed("theme") && cfg.Theme != "" {
+				themeName = cfg.Theme
+			}
+			if !cmd.Flags().Changed("preview-depth") && cfg.PreviewDepth > 0 {
+				previewDepth = cfg.PreviewDepth
+			}
+			if !cmd.Flags().Changed("preview-items") && cfg.PreviewItems > 0 {
+				previewItems = cfg.PreviewItems
+			}
+			if err := tui.ApplyTheme(themeName, colorMode); err != nil {
+				return err
+			}
+
+			keys := tui.DefaultKeyMap()
+			if err := keys.Apply(cfg.Keys); err != nil {
+				return err
+			}
+
+			if searchMode && regexMode {
+				return fmt.Errorf("--search and --regex can't be used together")
+			}
+
+			query := scan.Query{Presets: presets}
+			switch {
+			case searchMode:
+				query.Mode = scan.MatchGlob
+			case regexMode:
+				query.Mode = scan.MatchRegex
+			}
 			if len(args) > 0 {
-				targetFolder = args[0]
+				query.Name = args[0]
 			}
 
-			if targetFolder == "" {
-				inputResult, err := tui.RunInput("node_modules")
+			walkOpts.Cache = !noCache
+			walkOpts.Type, err = scan.ParseEntryType(entryType)
+			if err != nil {
+				return err
+			}
+			if olderThan != "" {
+				walkOpts.OlderThan, err = parseAge(olderThan)
 				if err != nil {
 					return err
 				}
-				if !inputResult.Submitted {
-					return nil
+			}
+			if minSize != "" {
+				walkOpts.MinSize, err = parseSize(minSize)
+				if err != nil {
+					return err
 				}
-				targetFolder = inputResult.Value
+			}
+			var free int64
+			if freeTarget != "" {
+				free, err = parseSize(freeTarget)
+				if err != nil {
+					return err
+				}
+			}
+			if freeBy != "largest" && freeBy != "oldest" {
+				return fmt.Errorf("invalid --free-by %q (want largest or oldest)", freeBy)
+			}
+			if walkOpts.MaxDepth > 0 && walkOpts.MinDepth > walkOpts.MaxDepth {
+				return fmt.Errorf("--min-depth %d is greater than --max-depth %d", walkOpts.MinDepth, walkOpts.MaxDepth)
 			}
 
-			root, err := os.Getwd()
+			roots, err := resolveRoots(append(rootPaths, args[min(len(args), 1):]...))
 			if err != nil {
 				return err
 			}
 
-			var results []scan.Result
-			if searchMode {
-				results, err = scan.FindFoldersGlob(root, targetFolder)
-			} else {
-				results, err = scan.FindFolders(root, targetFolder)
+			if query.Name == "" && len(query.Presets) == 0 {
+				inputResult, err := tui.RunInput("node_modules", roots, query)
+				if err != nil {
+					return err
+				}
+				if !inputResult.Submitted {
+					return nil
+				}
+				query = inputResult.Query
 			}
+
+			find := func(q scan.Query) ([]scan.Result, error) {
+				results, err := scan.FindAll(roots, q, walkOpts)
+				if err != nil || free == 0 {
+					return results, err
+				}
+				scan.MeasureSizes(results, walkOpts)
+				if freeBy == "oldest" {
+					scan.MeasureAges(results, walkOpts.ProjectAge)
+				}
+				return results, nil
+			}
+
+			results, err := find(query)
 			if err != nil {
 				return err
 			}
 
 			if len(results) == 0 {
-				fmt.Println("No matching folders found.")
+				noun := "folders"
+				switch walkOpts.Type {
+				case scan.TypeFile:
+					noun = "files"
+				case scan.TypeAny:
+					noun = "files or folders"
+				}
+				fmt.Printf("No matching %s found.\n", noun)
+				if suggestion := scan.Suggest(roots, query, walkOpts); suggestion != "" {
+					fmt.Printf("Did you mean %q?\n", suggestion)
+				}
 				return nil
 			}
+			// History is a convenience; failing to record it shouldn't stop a search.
+			_ = history.Add(query.Name)
 
-			tuiResult, err := tui.RunSelector(results)
+			tuiResult, err := tui.RunSelector(results, tui.Options{
+				Roots:        roots,
+				Keys:         keys,
+				PreviewDepth: previewDepth,
+				PreviewItems: previewItems,
+				Query:        query,
+				WalkOptions:  walkOpts,
+				Scan:         find,
+				Free:         free,
+				FreeOldest:   freeBy == "oldest",
+			})
 			if err != nil {
 				return err
 			}
 
 			if tuiResult.DeleteConfirmed && len(tuiResult.ToDelete) > 0 {
-				_, err := tui.RunDelete(tuiResult.ToDelete)
+				_, err := tui.RunDelete(tuiResult.ToDelete, roots)
 				if err != nil {
 					return err
 				}
@@ -71,6 +187,55 @@ func Execute() error {
 	}
 
 	rootCmd.Flags().BoolVarP(&searchMode, "search", "s", false, "Enable search mode with glob patterns")
+	rootCmd.Flags().BoolVarP(&regexMode, "regex", "e", false, "Match folders with a regular expression")
+	rootCmd.Flags().StringArrayVar(&rootPaths, "root", nil, "Directory to search instead of the current one (repeatable)")
+	rootCmd.Flags().IntVar(&walkOpts.MinDepth, "min-depth", 0, "Ignore matches fewer than N levels below a root")
+	rootCmd.Flags().IntVar(&walkOpts.MaxDepth, "max-depth", 0, "Search at most N levels below a root (0 for no limit)")
+	rootCmd.Flags().BoolVarP(&walkOpts.OneFileSystem, "one-file-system", "x", false, "Don't descend into other mounted filesystems")
+	rootCmd.Flags().BoolVarP(&walkOpts.FollowSymlinks, "follow-symlinks", "L", false, "Follow symlinked directories, skipping any already visited")
+	rootCmd.Flags().StringVarP(&entryType, "type", "t", "d", "What to match: d (folders), f (files) or any")
+	rootCmd.Flags().BoolVar(&walkOpts.Nested, "nested", false, "Keep searching inside matched folders and report nested matches")
+	rootCmd.Flags().StringVar(&olderThan, "older-than", "", "Only show results untouched for this long, e.g. 60d, 12w, 1y")
+	rootCmd.Flags().BoolVar(&walkOpts.ProjectAge, "project-age", false, "Judge age by the newest file in the enclosing project")
+	rootCmd.Flags().StringVar(&minSize, "min-size", "", "Hide results smaller than this, e.g. 500M, 1G")
+	rootCmd.Flags().BoolVar(&walkOpts.ApparentSize, "apparent-size", false, "Measure apparent file sizes instead of disk usage")
+	rootCmd.Flags().StringVar(&freeTarget, "free", "", "Pre-select results until this much space is freed, e.g. 20G")
+	rootCmd.Flags().StringVar(&freeBy, "free-by", "largest", "Which results --free picks first: largest or oldest")
+	rootCmd.Flags().BoolVar(&noCache, "no-cache", false, "Ignore the scan index and read every folder from disk")
+	rootCmd.Flags().StringSliceVar(&presets, "preset", nil, "Also match the folders of a preset (repeatable): "+strings.Join(scan.PresetNames(), ", "))
+	rootCmd.Flags().StringVar(&colorMode, "color", "auto", "When to use colors: never, auto or always")
+	rootCmd.Flags().IntVar(&previewDepth, "preview-depth", tui.DefaultPreviewDepth, "Number of levels loaded when previewing a folder")
+	rootCmd.Flags().IntVar(&previewItems, "preview-items", tui.DefaultPreviewItems, "Maximum number of entries loaded when previewing a folder")
+	rootCmd.Flags().StringVar(&themeName, "theme", "auto", "Color theme: auto, dark, light, high-contrast or monochrome")
+
+	rootCmd.AddCommand(newCacheCmd())
 
 	return rootCmd.ExecuteContext(context.Background())
 }
+
+func resolveRoots(paths []string) ([]string, error) {
+	if len(paths) == 0 {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return nil, err
+		}
+		return []string{cwd}, nil
+	}
+
+	roots := make([]string, 0, len(paths))
+	for _, p := range paths {
+		abs, err := filepath.Abs(p)
+		if err != nil {
+			return nil, err
+		}
+		info, err := os.Stat(abs)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			return nil, fmt.Errorf("%s is not a directory", p)
+		}
+		roots = append(roots, abs)
+	}
+	return roots, nil
+}
@@ -0,0 +1,49 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+type Config struct {
+	Theme        string              `json:"theme"`
+	Color        string              `json:"color"`
+	Keys         map[string][]string `json:"keys"`
+	PreviewDepth int                 `json:"preview_depth"`
+	PreviewItems int                 `json:"preview_items"`
+}
+
+func Path() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "zap", "config.json"), nil
+}
+
+func Load() (Config, error) {
+	var cfg Config
+
+	path, err := Path()
+	if err != nil {
+		return cfg, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("error parsing %s: %w", path, err)
+	}
+
+	return cfg, nil
+}
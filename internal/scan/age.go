@@ -0,0 +1,112 @@
+package scan
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+var projectMarkers = []string{
+	".git", "package.json", "go.mod", "Cargo.toml", "pyproject.toml", "setup.py",
+	"requirements.txt", "pom.xml", "build.gradle", "build.gradle.kts", "Gemfile",
+	"mix.exs", "composer.json", "Package.swift",
+}
+
+func NewestModTime(path string, skip func(dir string) bool) time.Time {
+	var newest time.Time
+
+	real := resolveRoot(path)
+	filepath.WalkDir(real, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || p == real {
+			return nil
+		}
+		if d.IsDir() && skip != nil && skip(path+p[len(real):]) {
+			return filepath.SkipDir
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		if t := info.ModTime(); t.After(newest) {
+			newest = t
+		}
+		return nil
+	})
+
+	if newest.IsZero() {
+		if info, err := os.Lstat(path); err == nil {
+			newest = info.ModTime()
+		}
+	}
+	return newest
+}
+
+func ProjectRoot(path, root string) string {
+	parent := filepath.Dir(path)
+	for dir := parent; isWithin(root, dir); dir = filepath.Dir(dir) {
+		for _, marker := range projectMarkers {
+			if _, err := os.Lstat(filepath.Join(dir, marker)); err == nil {
+				return dir
+			}
+		}
+		if dir == root {
+			break
+		}
+	}
+	return parent
+}
+
+func isWithin(root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// In project mode the match itself and any other matched folders are left
+// out, so a fresh install inside an idle project doesn't make it look recent.
+func (r Result) LastModified(project bool, matched map[string]bool) time.Time {
+	if project && !r.IsFile {
+		return NewestModTime(ProjectRoot(r.Path, r.Root), func(dir string) bool {
+			return dir == r.Path || matched[dir]
+		})
+	}
+	if r.IsFile {
+		if info, err := os.Lstat(r.Target()); err == nil {
+			return info.ModTime()
+		}
+	}
+	return NewestModTime(r.Target(), nil)
+}
+
+func MeasureAges(results []Result, project bool) {
+	matched := MatchedDirs(results)
+	forEach(results, func(i int) {
+		if r := &results[i]; r.Newest.IsZero() {
+			r.Newest = r.LastModified(project, matched)
+		}
+	})
+}
+
+func MatchedDirs(results []Result) map[string]bool {
+	matched := make(map[string]bool)
+	for _, r := range results {
+		if !r.IsFile {
+			matched[r.Path] = true
+		}
+	}
+	return matched
+}
+
+func filterOlderThan(results []Result, opts Options) []Result {
+	cutoff := time.Now().Add(-opts.OlderThan)
+	MeasureAges(results, opts.ProjectAge)
+
+	kept := results[:0]
+	for _, r := range results {
+		if r.Newest.Before(cutoff) {
+			kept = append(kept, r)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,31 @@
+package scan
+
+import "path/filepath"
+
+type Disk struct {
+	Mount string
+	Total uint64
+	Free  uint64
+}
+
+func (d Disk) Used() uint64 {
+	return d.Total - d.Free
+}
+
+func DiskOf(path string) (Disk, error) {
+	total, free, err := diskSpace(path)
+	if err != nil {
+		return Disk{}, err
+	}
+	return Disk{Mount: mountOf(path), Total: total, Free: free}, nil
+}
+
+func mountOf(path string) string {
+	for {
+		parent := filepath.Dir(path)
+		if parent == path || IsMountPoint(path) {
+			return path
+		}
+		path = parent
+	}
+}
@@ -0,0 +1,11 @@
+package scan
+
+import "syscall"
+
+func diskSpace(path string) (total, free uint64, err error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return 0, 0, err
+	}
+	return st.F_blocks * uint64(st.F_bsize), uint64(st.F_bavail) * uint64(st.F_bsize), nil
+}
@@ -0,0 +1,9 @@
+//go:build !linux && !darwin && !freebsd && !dragonfly && !openbsd && !windows
+
+package scan
+
+import "errors"
+
+func diskSpace(path string) (total, free uint64, err error) {
+	return 0, 0, errors.ErrUnsupported
+}
@@ -0,0 +1,49 @@
+package scan
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+func HeldOpen(paths []string) (size int64, procs []string) {
+	fds, _ := filepath.Glob("/proc/[0-9]*/fd/*")
+
+	seenFile := make(map[[2]uint64]bool)
+	seenProc := make(map[string]bool)
+	for _, fd := range fds {
+		target, err := os.Readlink(fd)
+		if err != nil || !strings.HasSuffix(target, " (deleted)") {
+			continue
+		}
+		target = strings.TrimSuffix(target, " (deleted)")
+		if !slices.ContainsFunc(paths, func(p string) bool { return isWithin(p, target) }) {
+			continue
+		}
+
+		info, err := os.Stat(fd)
+		if err != nil {
+			continue
+		}
+		if dev, ino, ok := fileID(info); ok {
+			if seenFile[[2]uint64{dev, ino}] {
+				continue
+			}
+			seenFile[[2]uint64{dev, ino}] = true
+		}
+		size += info.Size()
+
+		pid := filepath.Base(filepath.Dir(filepath.Dir(fd)))
+		comm, err := os.ReadFile(filepath.Join("/proc", pid, "comm"))
+		name := strings.TrimSpace(string(comm))
+		if err != nil || name == "" {
+			name = pid
+		}
+		if !seenProc[name] {
+			seenProc[name] = true
+			procs = append(procs, name)
+		}
+	}
+	return size, procs
+}
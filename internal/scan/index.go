@@ -0,0 +1,189 @@
+package scan
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/coeeter/zap/internal/cache"
+)
+
+const (
+	indexVersion = 3
+
+	// Listings of folders modified this recently aren't stored: a change
+	// within the same mtime tick would otherwise go unnoticed.
+	racyWindow = 2 * time.Second
+)
+
+type index struct {
+	Version int
+	Dirs    map[string]indexDir
+
+	name    string
+	files   bool
+	mu      sync.Mutex
+	removed map[string]bool
+	changed bool
+}
+
+type indexDir struct {
+	ModTime int64
+	Subdirs []string
+	Files   []string
+}
+
+// Searches only need folder names, so file names are kept in a separate
+// index that is loaded only when sizes are measured.
+func loadIndex(kind, root string, files bool) *index {
+	sum := sha256.Sum256([]byte(root))
+	name := filepath.Join(kind, hex.EncodeToString(sum[:8]))
+
+	ix := &index{}
+	if err := cache.Load(name, ix); err != nil || ix.Version != indexVersion {
+		ix = &index{Version: indexVersion}
+	}
+	if ix.Dirs == nil {
+		ix.Dirs = make(map[string]indexDir)
+	}
+	ix.name = name
+	ix.files = files
+	ix.removed = make(map[string]bool)
+	return ix
+}
+
+func (ix *index) save() {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	if !ix.changed {
+		return
+	}
+	for path := range ix.Dirs {
+		if len(ix.removed) > 0 && ix.isRemoved(path) {
+			delete(ix.Dirs, path)
+		}
+	}
+	ix.removed = make(map[string]bool)
+	ix.changed = false
+	// The index only saves time; a failed write just means a slower next scan.
+	_ = cache.Save(ix.name, ix)
+}
+
+func (ix *index) isRemoved(path string) bool {
+	for p := path; ; p = filepath.Dir(p) {
+		if ix.removed[p] {
+			return true
+		}
+		if filepath.Dir(p) == p {
+			return false
+		}
+	}
+}
+
+func (ix *index) listing(dir string, info os.FileInfo) indexDir {
+	mtime := info.ModTime().UnixNano()
+	ix.mu.Lock()
+	old, ok := ix.Dirs[dir]
+	ix.mu.Unlock()
+	if ok && old.ModTime == mtime {
+		return old
+	}
+
+	entries, err := os.ReadDir(dir)
+
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	ix.changed = true
+	if err != nil {
+		delete(ix.Dirs, dir)
+		return indexDir{}
+	}
+
+	l := indexDir{ModTime: mtime}
+	current := make(map[string]bool)
+	for _, e := range entries {
+		if e.IsDir() {
+			l.Subdirs = append(l.Subdirs, e.Name())
+			current[e.Name()] = true
+		} else if ix.files {
+			l.Files = append(l.Files, e.Name())
+		}
+	}
+
+	for _, name := range old.Subdirs {
+		if !current[name] {
+			ix.removed[filepath.Join(dir, name)] = true
+		}
+	}
+	if time.Since(info.ModTime()) > racyWindow {
+		ix.Dirs[dir] = l
+	} else {
+		delete(ix.Dirs, dir)
+	}
+	return l
+}
+
+// A SizeCache measures folders from remembered listings, reading a folder
+// again only when its modification time changes. Every entry is still
+// stat'ed on each measurement, since rewriting a file in place doesn't touch
+// its folder, so sizes are always current. A nil SizeCache measures directly.
+type SizeCache struct {
+	mu      sync.Mutex
+	indexes map[string]*index
+}
+
+func NewSizeCache() *SizeCache {
+	return &SizeCache{indexes: make(map[string]*index)}
+}
+
+func (c *SizeCache) Measure(root, path string) Measurement {
+	if c == nil {
+		return Measure(path)
+	}
+
+	c.mu.Lock()
+	ix, ok := c.indexes[root]
+	if !ok {
+		ix = loadIndex("sizes", root, true)
+		c.indexes[root] = ix
+	}
+	c.mu.Unlock()
+
+	var m Measurement
+	if info, err := os.Lstat(path); err == nil {
+		ix.measure(path, info, &m)
+	}
+	return m
+}
+
+func (c *SizeCache) Save() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, ix := range c.indexes {
+		ix.save()
+	}
+}
+
+func (ix *index) measure(path string, info os.FileInfo, m *Measurement) {
+	m.add(info)
+	if !info.IsDir() {
+		return
+	}
+
+	l := ix.listing(path, info)
+	for _, names := range [][]string{l.Subdirs, l.Files} {
+		for _, name := range names {
+			sub := filepath.Join(path, name)
+			if subInfo, err := os.Lstat(sub); err == nil {
+				ix.measure(sub, subInfo, m)
+			}
+		}
+	}
+}
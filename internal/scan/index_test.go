@@ -0,0 +1,221 @@
+package scan
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func setupCache(t *testing.T) {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", dir)
+	t.Setenv("HOME", dir)
+	t.Setenv("LocalAppData", dir)
+}
+
+func backdate(t *testing.T, root string) {
+	t.Helper()
+	old := time.Now().Add(-time.Hour)
+	err := filepath.Walk(root, func(p string, _ os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		return os.Chtimes(p, old, old)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func measure(t *testing.T, roots []string, q Query, opts Options) map[string]int64 {
+	t.Helper()
+	results, err := FindAll(roots, q, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	MeasureSizes(results, opts)
+
+	sizes := make(map[string]int64)
+	for _, r := range results {
+		sizes[r.Path] = r.Size
+	}
+	return sizes
+}
+
+func TestCachedScanSeesDeepChanges(t *testing.T) {
+	setupCache(t)
+	root := t.TempDir()
+	deep := filepath.Join(root, "app", "node_modules", ".cache", "webpack", "a")
+	if err := os.MkdirAll(deep, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	backdate(t, root)
+
+	q := Query{Name: "node_modules"}
+	opts := Options{Cache: true, ApparentSize: true}
+	measure(t, []string{root}, q, opts)
+
+	if err := os.WriteFile(filepath.Join(deep, "f2"), make([]byte, 1<<20), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	nm := filepath.Join(root, "app", "node_modules")
+	cached := measure(t, []string{root}, q, opts)
+	fresh := measure(t, []string{root}, q, Options{ApparentSize: true})
+	if cached[nm] != fresh[nm] || fresh[nm] != 1<<20 {
+		t.Errorf("cached size %d, uncached %d, want %d", cached[nm], fresh[nm], 1<<20)
+	}
+}
+
+func TestCachedSizeIndependentOfEarlierScans(t *testing.T) {
+	setupCache(t)
+	root := t.TempDir()
+	for _, dir := range []string{"x", "y"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(root, "x", "lib"), make([]byte, 1<<20), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Link(filepath.Join(root, "x", "lib"), filepath.Join(root, "y", "lib")); err != nil {
+		t.Skip("hard links not supported:", err)
+	}
+	backdate(t, root)
+
+	opts := Options{Cache: true, ApparentSize: true}
+	measure(t, []string{root}, Query{Name: "^(x|y)$", Mode: MatchRegex}, opts)
+
+	y := filepath.Join(root, "y")
+	cached := measure(t, []string{root}, Query{Name: "y"}, opts)
+	fresh := measure(t, []string{root}, Query{Name: "y"}, Options{ApparentSize: true})
+	if cached[y] != fresh[y] || fresh[y] != 1<<20 {
+		t.Errorf("cached size %d, uncached %d, want %d", cached[y], fresh[y], 1<<20)
+	}
+}
+
+func find(t *testing.T, root string, q Query) map[string]bool {
+	t.Helper()
+	results, err := Find(root, q, Options{Cache: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := make(map[string]bool)
+	for _, r := range results {
+		found[r.Path] = true
+	}
+	return found
+}
+
+func TestIndexFindsNewSubfolder(t *testing.T) {
+	setupCache(t)
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "a", "b"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	backdate(t, root)
+
+	q := Query{Name: "node_modules"}
+	if found := find(t, root, q); len(found) != 0 {
+		t.Fatalf("found %v in an empty tree", found)
+	}
+
+	nm := filepath.Join(root, "a", "b", "node_modules")
+	if err := os.Mkdir(nm, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	backdate(t, root)
+	if found := find(t, root, q); !found[nm] {
+		t.Errorf("new folder %s not found, got %v", nm, found)
+	}
+}
+
+func TestIndexDropsRemovedSubfolder(t *testing.T) {
+	setupCache(t)
+	root := t.TempDir()
+	nm := filepath.Join(root, "a", "x", "node_modules")
+	if err := os.MkdirAll(nm, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	backdate(t, root)
+
+	q := Query{Name: "node_modules"}
+	if found := find(t, root, q); !found[nm] {
+		t.Fatalf("%s not found, got %v", nm, found)
+	}
+
+	if err := os.RemoveAll(filepath.Join(root, "a", "x")); err != nil {
+		t.Fatal(err)
+	}
+	backdate(t, root)
+	if found := find(t, root, q); len(found) != 0 {
+		t.Errorf("removed folder still found: %v", found)
+	}
+
+	ix := loadIndex("index", root, false)
+	for dir := range ix.Dirs {
+		if strings.HasPrefix(dir, filepath.Join(root, "a", "x")) {
+			t.Errorf("index still lists removed folder %s", dir)
+		}
+	}
+}
+
+func TestIndexSkipsRecentListings(t *testing.T) {
+	setupCache(t)
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "a"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	find(t, root, Query{Name: "node_modules"})
+	if ix := loadIndex("index", root, false); len(ix.Dirs) != 0 {
+		t.Errorf("stored listings modified within the racy window: %v", ix.Dirs)
+	}
+
+	backdate(t, root)
+	find(t, root, Query{Name: "node_modules"})
+	if ix := loadIndex("index", root, false); len(ix.Dirs) != 2 {
+		t.Errorf("stored %d listings, want 2: %v", len(ix.Dirs), ix.Dirs)
+	}
+}
+
+func TestCachedSizeSeesRewrittenFile(t *testing.T) {
+	setupCache(t)
+	root := t.TempDir()
+	pkg := filepath.Join(root, "node_modules", "pkg")
+	if err := os.MkdirAll(pkg, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(pkg, "index.js")
+	if err := os.WriteFile(file, []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	backdate(t, root)
+
+	q := Query{Name: "node_modules"}
+	opts := Options{Cache: true, ApparentSize: true}
+	nm := filepath.Join(root, "node_modules")
+	if size := measure(t, []string{root}, q, opts)[nm]; size != 1 {
+		t.Fatalf("size %d, want 1", size)
+	}
+	if l := loadIndex("sizes", root, true).Dirs[pkg]; len(l.Files) != 1 {
+		t.Fatalf("listing of %s not stored: %+v", pkg, l)
+	}
+
+	// Appending to a file leaves its folder's modification time alone.
+	f, err := os.OpenFile(file, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Write(make([]byte, 4095)); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	if size := measure(t, []string{root}, q, opts)[nm]; size != 4096 {
+		t.Errorf("cached size %d after the file grew, want 4096", size)
+	}
+}
@@ -0,0 +1,86 @@
+package scan
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+type MatchMode int
+
+const (
+	MatchExact MatchMode = iota
+	MatchGlob
+	MatchRegex
+)
+
+func (m MatchMode) String() string {
+	switch m {
+	case MatchGlob:
+		return "glob"
+	case MatchRegex:
+		return "regex"
+	}
+	return "exact"
+}
+
+func compilePattern(pattern string, mode MatchMode) (func(name, rel string) bool, error) {
+	// Patterns with a slash match the slash-separated path relative to the
+	// root rather than the folder name.
+	byPath := strings.Contains(pattern, "/")
+
+	switch mode {
+	case MatchRegex:
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regex %q: %w", pattern, err)
+		}
+		if byPath {
+			return func(_, rel string) bool { return re.MatchString(rel) }, nil
+		}
+		return func(name, _ string) bool { return re.MatchString(name) }, nil
+
+	case MatchGlob:
+		segments := strings.Split(strings.Trim(pattern, "/"), "/")
+		for _, seg := range segments {
+			if _, err := path.Match(seg, ""); err != nil {
+				return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+			}
+		}
+		if byPath {
+			return func(_, rel string) bool { return matchSegments(segments, strings.Split(rel, "/")) }, nil
+		}
+		return func(name, _ string) bool {
+			matched, _ := path.Match(pattern, name)
+			return matched
+		}, nil
+	}
+
+	if byPath {
+		want := strings.Trim(pattern, "/")
+		return func(_, rel string) bool { return rel == want }, nil
+	}
+	return func(name, _ string) bool { return name == pattern }, nil
+}
+
+func matchSegments(pattern, parts []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(parts); i++ {
+				if matchSegments(pattern[1:], parts[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(parts) == 0 {
+			return false
+		}
+		if matched, _ := path.Match(pattern[0], parts[0]); !matched {
+			return false
+		}
+		pattern, parts = pattern[1:], parts[1:]
+	}
+	return len(parts) == 0
+}
@@ -0,0 +1,124 @@
+package scan
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+)
+
+func TestCompilePattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		mode    MatchMode
+		rel     string
+		want    bool
+	}{
+		{"node_modules", MatchExact, "app/node_modules", true},
+		{"node_modules", MatchExact, "app/node_modules2", false},
+		{"apps/web/.next", MatchExact, "apps/web/.next", true},
+		{"/apps/web/.next/", MatchExact, "apps/web/.next", true},
+		{"apps/web/.next", MatchExact, "x/apps/web/.next", false},
+		{"apps/web/.next", MatchExact, ".next", false},
+
+		{"node_*", MatchGlob, "a/b/node_modules", true},
+		{"node_*", MatchGlob, "a/node_modules/b", false},
+		{"**/android/app/build", MatchGlob, "android/app/build", true},
+		{"**/android/app/build", MatchGlob, "apps/mobile/android/app/build", true},
+		{"**/android/app/build", MatchGlob, "apps/mobile/android/app/build/intermediates", false},
+		{"**/android/app/build", MatchGlob, "ios/app/build", false},
+		{"packages/*/dist", MatchGlob, "packages/ui/dist", true},
+		{"packages/*/dist", MatchGlob, "packages/ui/src/dist", false},
+		{"packages/*/dist", MatchGlob, "other/packages/ui/dist", false},
+		{"packages/*/dist", MatchGlob, "dist", false},
+		{"**/dist", MatchGlob, "dist", true},
+		{"**/dist", MatchGlob, "a/b/c/dist", true},
+		{"a/**/dist", MatchGlob, "a/dist", true},
+		{"a/**/dist", MatchGlob, "a/x/y/dist", true},
+		{"a/**/dist", MatchGlob, "b/x/dist", false},
+
+		{`^\.?venv$`, MatchRegex, "py/.venv", true},
+		{`^\.?venv$`, MatchRegex, "py/venv2", false},
+		{`^packages/[^/]+/dist$`, MatchRegex, "packages/ui/dist", true},
+		{`^packages/[^/]+/dist$`, MatchRegex, "packages/ui/lib/dist", false},
+		{`/build$`, MatchRegex, "app/build", true},
+		{`/build$`, MatchRegex, "build", false},
+	}
+
+	for _, tt := range tests {
+		match, err := compilePattern(tt.pattern, tt.mode)
+		if err != nil {
+			t.Errorf("%s %q: %v", tt.mode, tt.pattern, err)
+			continue
+		}
+		if got := match(path.Base(tt.rel), tt.rel); got != tt.want {
+			t.Errorf("%s %q against %q = %v, want %v", tt.mode, tt.pattern, tt.rel, got, tt.want)
+		}
+	}
+}
+
+func TestCompilePatternErrors(t *testing.T) {
+	tests := []struct {
+		pattern string
+		mode    MatchMode
+	}{
+		{"[", MatchGlob},
+		{"packages/[/dist", MatchGlob},
+		{"**/a\\", MatchGlob},
+		{"(", MatchRegex},
+		{"a/(b", MatchRegex},
+	}
+
+	for _, tt := range tests {
+		if _, err := compilePattern(tt.pattern, tt.mode); err == nil {
+			t.Errorf("%s %q: expected an error", tt.mode, tt.pattern)
+		}
+	}
+}
+
+func TestFindMatchesPathRelativeToRoot(t *testing.T) {
+	dir := t.TempDir()
+	root := filepath.Join(dir, "repo")
+	for _, p := range []string{"packages/ui/dist", "packages/api/dist", "dist", "tools/dist"} {
+		if err := os.MkdirAll(filepath.Join(root, p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	link := filepath.Join(dir, "link")
+	if err := os.Symlink(root, link); err != nil {
+		t.Skip("symlinks not supported:", err)
+	}
+
+	tests := []struct {
+		root    string
+		pattern string
+		want    []string
+	}{
+		{root, "packages/*/dist", []string{"packages/api/dist", "packages/ui/dist"}},
+		{filepath.Join(root, "packages"), "*/dist", []string{"api/dist", "ui/dist"}},
+		{filepath.Join(root, "packages"), "packages/*/dist", nil},
+		{link, "packages/*/dist", []string{"packages/api/dist", "packages/ui/dist"}},
+		{root, "**/dist", []string{"dist", "packages/api/dist", "packages/ui/dist", "tools/dist"}},
+	}
+
+	for _, tt := range tests {
+		results, err := Find(tt.root, Query{Name: tt.pattern, Mode: MatchGlob}, Options{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, r := range results {
+			got = append(got, relPath(tt.root, r.Path))
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("%q under %s = %v, want %v", tt.pattern, tt.root, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%q under %s = %v, want %v", tt.pattern, tt.root, got, tt.want)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,378 @@
+package scan
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+var Presets = map[string][]string{
+	"node":   {"node_modules", ".next", ".nuxt", ".parcel-cache", ".turbo"},
+	"python": {"__pycache__", ".venv", ".pytest_cache", ".mypy_cache", ".tox"},
+	"rust":   {"target"},
+	"gradle": {".gradle"},
+	"maven":  {"target"},
+	"dotnet": {"bin", "obj"},
+	"swift":  {".build", "DerivedData"},
+	"go":     {"vendor"},
+	"ruby":   {".bundle"},
+	"elixir": {"_build", "deps"},
+}
+
+func PresetNames() []string {
+	names := make([]string, 0, len(Presets))
+	for name := range Presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+type EntryType int
+
+const (
+	TypeDir EntryType = iota
+	TypeFile
+	TypeAny
+)
+
+func ParseEntryType(s string) (EntryType, error) {
+	switch s {
+	case "d", "dir":
+		return TypeDir, nil
+	case "f", "file":
+		return TypeFile, nil
+	case "any":
+		return TypeAny, nil
+	}
+	return TypeDir, fmt.Errorf("invalid type %q (want f, d or any)", s)
+}
+
+type Options struct {
+	Type           EntryType
+	MinDepth       int
+	MaxDepth       int
+	OneFileSystem  bool
+	FollowSymlinks bool
+	Nested         bool
+	OlderThan      time.Duration
+	ProjectAge     bool
+	MinSize        int64
+	ApparentSize   bool
+	Cache          bool
+}
+
+type Query struct {
+	Name    string
+	Mode    MatchMode
+	Presets []string
+}
+
+func (q Query) String() string {
+	var parts []string
+	if q.Name != "" {
+		if q.Mode != MatchExact {
+			parts = append(parts, q.Mode.String()+" "+q.Name)
+		} else {
+			parts = append(parts, q.Name)
+		}
+	}
+	for _, p := range q.Presets {
+		parts = append(parts, "preset "+p)
+	}
+	return strings.Join(parts, " + ")
+}
+
+func (q Query) matcher() (func(name, rel string) bool, error) {
+	names := make(map[string]bool)
+	for _, p := range q.Presets {
+		folders, ok := Presets[p]
+		if !ok {
+			return nil, fmt.Errorf("unknown preset %q (want %s)", p, strings.Join(PresetNames(), ", "))
+		}
+		for _, f := range folders {
+			names[f] = true
+		}
+	}
+
+	match := func(string, string) bool { return false }
+	if q.Name != "" {
+		var err error
+		match, err = compilePattern(q.Name, q.Mode)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return func(name, rel string) bool {
+		return names[name] || match(name, rel)
+	}, nil
+}
+
+type finder struct {
+	root       string
+	real       string
+	match      func(name, rel string) bool
+	opts       Options
+	sameDevice func(fs.DirEntry) bool
+	visited    map[fileKey]bool
+	index      *index
+	results    []Result
+}
+
+func Find(root string, q Query, opts Options) ([]Result, error) {
+	match, err := q.matcher()
+	if err != nil {
+		return nil, err
+	}
+
+	f := &finder{
+		root:       root,
+		real:       resolveRoot(root),
+		match:      match,
+		opts:       opts,
+		sameDevice: deviceFilter(root, opts.OneFileSystem),
+		visited:    make(map[fileKey]bool),
+	}
+
+	// The index only records folders, so file and symlink walks always read
+	// the tree directly.
+	if opts.Cache && opts.Type == TypeDir && !opts.FollowSymlinks {
+		f.index = loadIndex("index", root, false)
+		if info, err := os.Lstat(f.real); err == nil && info.IsDir() {
+			f.walkIndexed(f.real, info)
+		}
+		f.index.save()
+		return f.results, nil
+	}
+
+	err = f.walk(f.real, root)
+	return f.results, err
+}
+
+// A symlinked root is walked at its real location, but results keep the path
+// the user gave.
+func resolveRoot(root string) string {
+	if real, err := filepath.EvalSymlinks(root); err == nil {
+		return real
+	}
+	return root
+}
+
+func (f *finder) viaLink(path, shown string) bool {
+	rel, ok := strings.CutPrefix(path, f.real)
+	return f.opts.FollowSymlinks && (!ok || f.root+rel != shown)
+}
+
+func (f *finder) walk(dir, via string) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+
+		// dir differs from via only when it is the target of a followed
+		// symlink; results are reported under the path they were reached by.
+		shown := via + path[len(dir):]
+		if d.Type()&fs.ModeSymlink != 0 && f.opts.FollowSymlinks {
+			f.follow(path, shown)
+			return nil
+		}
+
+		name := filepath.Base(shown)
+		if !d.IsDir() {
+			if f.opts.Type != TypeDir && d.Type().IsRegular() {
+				f.matchFile(path, shown, name, d)
+			}
+			return nil
+		}
+
+		switch name {
+		case ".git", ".idea", ".vscode":
+			return filepath.SkipDir
+		}
+
+		if path != f.real && !f.sameDevice(d) {
+			return filepath.SkipDir
+		}
+
+		if f.opts.FollowSymlinks {
+			key, ok := keyOf(path, d)
+			if !ok || f.visited[key] {
+				return filepath.SkipDir
+			}
+			f.visited[key] = true
+		}
+
+		depth := pathDepth(f.root, shown)
+		if f.opts.Type != TypeFile && f.match(name, relPath(f.root, shown)) {
+			if depth >= f.opts.MinDepth {
+				r := Result{Path: shown, Root: f.root}
+				if f.viaLink(path, shown) {
+					r.RealPath = path
+				}
+				f.results = append(f.results, r)
+			}
+			if !f.opts.Nested {
+				return filepath.SkipDir
+			}
+		}
+
+		if f.opts.MaxDepth > 0 && depth >= f.opts.MaxDepth {
+			return filepath.SkipDir
+		}
+
+		return nil
+	})
+}
+
+func (f *finder) walkIndexed(path string, info os.FileInfo) {
+	shown := f.root + path[len(f.real):]
+	name := filepath.Base(shown)
+	switch name {
+	case ".git", ".idea", ".vscode":
+		return
+	}
+
+	if path != f.real && !f.sameDevice(fs.FileInfoToDirEntry(info)) {
+		return
+	}
+
+	depth := pathDepth(f.root, shown)
+	if f.match(name, relPath(f.root, shown)) {
+		if depth >= f.opts.MinDepth {
+			f.results = append(f.results, Result{Path: shown, Root: f.root})
+		}
+		if !f.opts.Nested {
+			return
+		}
+	}
+
+	if f.opts.MaxDepth > 0 && depth >= f.opts.MaxDepth {
+		return
+	}
+
+	for _, sub := range f.index.listing(path, info).Subdirs {
+		subPath := filepath.Join(path, sub)
+		if subInfo, err := os.Lstat(subPath); err == nil && subInfo.IsDir() {
+			f.walkIndexed(subPath, subInfo)
+		}
+	}
+}
+
+func (f *finder) matchFile(path, shown, name string, d fs.DirEntry) {
+	if pathDepth(f.root, shown) < f.opts.MinDepth || !f.match(name, relPath(f.root, shown)) {
+		return
+	}
+
+	r := Result{Path: shown, Root: f.root, IsFile: true}
+	if f.viaLink(path, shown) {
+		r.RealPath = path
+	}
+	if info, err := d.Info(); err == nil {
+		r.Size = FileSize(info, f.opts.ApparentSize)
+	}
+	f.results = append(f.results, r)
+}
+
+func (f *finder) follow(link, shown string) {
+	real, err := filepath.EvalSymlinks(link)
+	if err != nil {
+		return
+	}
+	info, err := os.Stat(real)
+	if err != nil || !info.IsDir() {
+		return
+	}
+	f.walk(real, shown)
+}
+
+type fileKey struct {
+	dev, ino uint64
+	path     string
+}
+
+func keyOf(path string, d fs.DirEntry) (fileKey, bool) {
+	info, err := d.Info()
+	if err != nil {
+		return fileKey{}, false
+	}
+	if dev, ino, ok := fileID(info); ok {
+		return fileKey{dev: dev, ino: ino}, true
+	}
+	return fileKey{path: path}, true
+}
+
+func deviceFilter(root string, enabled bool) func(fs.DirEntry) bool {
+	all := func(fs.DirEntry) bool { return true }
+	if !enabled {
+		return all
+	}
+
+	info, err := os.Stat(root)
+	if err != nil {
+		return all
+	}
+	rootDev, ok := deviceID(info)
+	if !ok {
+		return all
+	}
+
+	return func(d fs.DirEntry) bool {
+		info, err := d.Info()
+		if err != nil {
+			return false
+		}
+		dev, ok := deviceID(info)
+		return !ok || dev == rootDev
+	}
+}
+
+func relPath(root, path string) string {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return path
+	}
+	return filepath.ToSlash(rel)
+}
+
+func pathDepth(root, path string) int {
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == "." {
+		return 0
+	}
+	return strings.Count(rel, string(filepath.Separator)) + 1
+}
+
+func FindAll(roots []string, q Query, opts Options) ([]Result, error) {
+	var results []Result
+	seen := make(map[string]bool)
+
+	for _, root := range roots {
+		found, err := Find(root, q, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range found {
+			key := r.Path
+			if r.RealPath != "" {
+				key = r.RealPath
+			}
+			if !seen[key] {
+				seen[key] = true
+				results = append(results, r)
+			}
+		}
+	}
+
+	if opts.OlderThan > 0 {
+		results = filterOlderThan(results, opts)
+	}
+	if opts.MinSize > 0 {
+		results = filterMinSize(results, opts)
+	}
+	return results, nil
+}
@@ -1,71 +1,27 @@
 package scan
 
-import (
-	"io/fs"
-	"path/filepath"
-)
+import "time"
 
 type Result struct {
-	Path string
+	Path     string
+	Root     string
+	RealPath string
+	IsFile   bool
+	Size     int64
+	Newest   time.Time
 }
 
-func FindFolders(root, name string) ([]Result, error) {
-	var results []Result
-
-	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
-		if err != nil {
-			return nil
-		}
-
-		if !d.IsDir() {
-			return nil
-		}
-
-		switch d.Name() {
-		case ".git", ".idea", ".vscode":
-			return filepath.SkipDir
-		}
-
-		if d.Name() == name {
-			results = append(results, Result{Path: path})
-			return filepath.SkipDir
-		}
-
-		return nil
-	})
+func (r Result) Target() string {
+	if r.RealPath != "" {
+		return r.RealPath
+	}
+	return r.Path
+}
 
-	return results, err
+func FindFolders(root, name string) ([]Result, error) {
+	return Find(root, Query{Name: name}, Options{})
 }
 
 func FindFoldersGlob(root string, pattern string) ([]Result, error) {
-	var results []Result
-
-	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
-		if err != nil {
-			return nil
-		}
-
-		if !d.IsDir() {
-			return nil
-		}
-
-		switch d.Name() {
-		case ".git", ".idea", ".vscode":
-			return filepath.SkipDir
-		}
-
-		matched, err := filepath.Match(pattern, d.Name())
-		if err != nil {
-			return err
-		}
-
-		if matched {
-			results = append(results, Result{Path: path})
-			return filepath.SkipDir
-		}
-
-		return nil
-	})
-
-	return results, err
+	return Find(root, Query{Name: pattern, Mode: MatchGlob}, Options{})
 }
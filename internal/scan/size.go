@@ -0,0 +1,152 @@
+package scan
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+)
+
+type Usage struct {
+	Apparent int64
+	Disk     int64
+	Shared   int64
+}
+
+func (u Usage) Size(apparent bool) int64 {
+	if apparent {
+		return u.Apparent
+	}
+	return u.Disk
+}
+
+func (u Usage) Add(o Usage) Usage {
+	return Usage{Apparent: u.Apparent + o.Apparent, Disk: u.Disk + o.Disk, Shared: u.Shared + o.Shared}
+}
+
+// A Measurement is a path's usage with its hard-linked files kept apart, so
+// a Sizer can count each of them once no matter which order paths were
+// measured in.
+type Measurement struct {
+	Usage Usage
+	links []linkedFile
+}
+
+type linkedFile struct {
+	key      [2]uint64
+	apparent int64
+	disk     int64
+}
+
+func Measure(path string) Measurement {
+	var m Measurement
+
+	filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			m.add(info)
+		}
+		return nil
+	})
+
+	return m
+}
+
+func (m *Measurement) add(info os.FileInfo) {
+	if info.IsDir() {
+		m.Usage.Disk += allocated(info)
+		return
+	}
+	if linkCount(info) > 1 {
+		if dev, ino, ok := fileID(info); ok {
+			m.links = append(m.links, linkedFile{key: [2]uint64{dev, ino}, apparent: info.Size(), disk: allocated(info)})
+			return
+		}
+	}
+	m.Usage.Apparent += info.Size()
+	m.Usage.Disk += allocated(info)
+}
+
+// A Sizer counts each hard-linked file once, for the first measurement added
+// that contains it. It isn't safe for concurrent use; add measurements in a
+// fixed order to get the same answer every time.
+type Sizer struct {
+	seen map[[2]uint64]bool
+}
+
+func NewSizer() *Sizer {
+	return &Sizer{seen: make(map[[2]uint64]bool)}
+}
+
+func (s *Sizer) Add(m Measurement) Usage {
+	u := m.Usage
+	for _, l := range m.links {
+		if s.seen[l.key] {
+			u.Shared += l.disk
+			continue
+		}
+		s.seen[l.key] = true
+		u.Apparent += l.apparent
+		u.Disk += l.disk
+	}
+	return u
+}
+
+func FileSize(info os.FileInfo, apparent bool) int64 {
+	if apparent {
+		return info.Size()
+	}
+	return allocated(info)
+}
+
+func MeasureSizes(results []Result, opts Options) {
+	var sizes *SizeCache
+	if opts.Cache {
+		sizes = NewSizeCache()
+		defer sizes.Save()
+	}
+
+	measured := make([]Measurement, len(results))
+	forEach(results, func(i int) {
+		if r := results[i]; !r.IsFile && r.Size == 0 {
+			measured[i] = sizes.Measure(r.Root, r.Target())
+		}
+	})
+
+	sizer := NewSizer()
+	for i := range results {
+		if r := &results[i]; !r.IsFile && r.Size == 0 {
+			r.Size = sizer.Add(measured[i]).Size(opts.ApparentSize)
+		}
+	}
+}
+
+func filterMinSize(results []Result, opts Options) []Result {
+	MeasureSizes(results, opts)
+
+	kept := results[:0]
+	for _, r := range results {
+		if r.Size >= opts.MinSize {
+			kept = append(kept, r)
+		}
+	}
+	return kept
+}
+
+func forEach(results []Result, fn func(i int)) {
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, runtime.NumCPU())
+	for i := range results {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			fn(i)
+		}(i)
+	}
+	wg.Wait()
+}
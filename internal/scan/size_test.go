@@ -0,0 +1,45 @@
+package scan
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSharedLinkChargedToFirstResult(t *testing.T) {
+	root := t.TempDir()
+	names := []string{"a", "b", "c", "d"}
+	for _, n := range names {
+		if err := os.Mkdir(filepath.Join(root, n), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	orig := filepath.Join(root, "d", "f")
+	if err := os.WriteFile(orig, make([]byte, 4096), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, n := range names[:3] {
+		if err := os.Link(orig, filepath.Join(root, n, "f")); err != nil {
+			t.Skip("hard links not supported:", err)
+		}
+	}
+
+	opts := Options{ApparentSize: true}
+	for run := 0; run < 20; run++ {
+		results := make([]Result, len(names))
+		for i, n := range names {
+			results[i] = Result{Path: filepath.Join(root, n), Root: root}
+		}
+		MeasureSizes(results, opts)
+
+		for i, r := range results {
+			want := int64(0)
+			if i == 0 {
+				want = 4096
+			}
+			if r.Size != want {
+				t.Fatalf("run %d: %s = %d, want %d", run, names[i], r.Size, want)
+			}
+		}
+	}
+}
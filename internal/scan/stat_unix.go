@@ -0,0 +1,63 @@
+//go:build !windows
+
+package scan
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+func IsMountPoint(path string) bool {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return false
+	}
+	parent, err := os.Lstat(filepath.Dir(path))
+	if err != nil {
+		return false
+	}
+
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return false
+	}
+	pst, ok := parent.Sys().(*syscall.Stat_t)
+	if !ok {
+		return false
+	}
+
+	return st.Dev != pst.Dev || st.Ino == pst.Ino
+}
+
+func deviceID(info os.FileInfo) (uint64, bool) {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, false
+	}
+	return uint64(st.Dev), true
+}
+
+func allocated(info os.FileInfo) int64 {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return info.Size()
+	}
+	return int64(st.Blocks) * 512
+}
+
+func linkCount(info os.FileInfo) uint64 {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 1
+	}
+	return uint64(st.Nlink)
+}
+
+func fileID(info os.FileInfo) (dev, ino uint64, ok bool) {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, 0, false
+	}
+	return uint64(st.Dev), uint64(st.Ino), true
+}
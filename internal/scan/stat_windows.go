@@ -0,0 +1,38 @@
+//go:build windows
+
+package scan
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+func IsMountPoint(path string) bool {
+	return false
+}
+
+func deviceID(info os.FileInfo) (uint64, bool) {
+	return 0, false
+}
+
+func diskSpace(path string) (total, free uint64, err error) {
+	name, err := windows.UTF16PtrFromString(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	err = windows.GetDiskFreeSpaceEx(name, &free, &total, nil)
+	return total, free, err
+}
+
+func allocated(info os.FileInfo) int64 {
+	return info.Size()
+}
+
+func linkCount(info os.FileInfo) uint64 {
+	return 1
+}
+
+func fileID(info os.FileInfo) (dev, ino uint64, ok bool) {
+	return 0, 0, false
+}
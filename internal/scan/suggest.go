@@ -0,0 +1,101 @@
+package scan
+
+import (
+	"io/fs"
+	"path/filepath"
+	"strings"
+)
+
+const maxDirNames = 5000
+
+func DirNames(root string, maxDepth int) []string {
+	var names []string
+	seen := make(map[string]bool)
+
+	walkDirNames(root, Options{MaxDepth: maxDepth}, func(name string) bool {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+		return len(names) < maxDirNames
+	})
+
+	return names
+}
+
+func Suggest(roots []string, q Query, opts Options) string {
+	if q.Name == "" || q.Mode != MatchExact {
+		return ""
+	}
+
+	target := strings.ToLower(q.Name)
+	best, bestDist := "", len(target)/4+1
+	seen := make(map[string]bool)
+
+	for _, root := range roots {
+		walkDirNames(root, opts, func(name string) bool {
+			if seen[name] || name == q.Name {
+				return true
+			}
+			seen[name] = true
+			if d := editDistance(target, strings.ToLower(name)); d < bestDist {
+				best, bestDist = name, d
+			}
+			return true
+		})
+	}
+
+	return best
+}
+
+func walkDirNames(root string, opts Options, fn func(name string) bool) {
+	sameDevice := deviceFilter(root, opts.OneFileSystem)
+	root = resolveRoot(root)
+
+	filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() || path == root {
+			return nil
+		}
+
+		switch d.Name() {
+		case ".git", ".idea", ".vscode":
+			return filepath.SkipDir
+		}
+
+		if !sameDevice(d) {
+			return filepath.SkipDir
+		}
+
+		if !fn(d.Name()) {
+			return filepath.SkipAll
+		}
+
+		if opts.MaxDepth > 0 && pathDepth(root, path) >= opts.MaxDepth {
+			return filepath.SkipDir
+		}
+		return nil
+	})
+}
+
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
@@ -0,0 +1,88 @@
+package tui
+
+import (
+	"fmt"
+	"runtime"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/coeeter/zap/internal/scan"
+)
+
+// Ages walk whole trees, so they get their own, smaller pool rather than
+// queueing ahead of preview sizes in sizeSlots.
+var ageSlots = make(chan struct{}, max(runtime.NumCPU()/2, 1))
+
+type ageMsg struct {
+	path   string
+	newest time.Time
+}
+
+// Ages are only shown in the list, so only rows on screen are aged; the rest
+// are picked up as they scroll into view.
+func (m *Model) requestAges() tea.Cmd {
+	if m.Mode != ModeList {
+		return nil
+	}
+
+	visibleHeight := m.visibleHeight()
+	start := scrollOffset(m.ListOffset, m.Cursor, visibleHeight)
+	end := min(start+visibleHeight, len(m.Items))
+
+	var cmds []tea.Cmd
+	var matched map[string]bool
+	for i := start; i < end; i++ {
+		item := &m.Items[i]
+		if item.aging || !item.Result.Newest.IsZero() {
+			continue
+		}
+		if matched == nil {
+			results := make([]scan.Result, len(m.Items))
+			for j, it := range m.Items {
+				results[j] = it.Result
+			}
+			matched = scan.MatchedDirs(results)
+		}
+		item.aging = true
+		cmds = append(cmds, ageResult(item.Result, m.WalkOptions.ProjectAge, matched))
+	}
+	return tea.Batch(cmds...)
+}
+
+func ageResult(r scan.Result, project bool, matched map[string]bool) tea.Cmd {
+	return func() tea.Msg {
+		ageSlots <- struct{}{}
+		defer func() { <-ageSlots }()
+		return ageMsg{path: r.Path, newest: r.LastModified(project, matched)}
+	}
+}
+
+func (m *Model) applyAge(msg ageMsg) {
+	for i := range m.Items {
+		if m.Items[i].Result.Path == msg.path {
+			m.Items[i].Result.Newest = msg.newest
+			return
+		}
+	}
+}
+
+func formatAge(t time.Time) string {
+	if t.IsZero() {
+		return "…"
+	}
+
+	d := time.Since(t)
+	switch {
+	case d < time.Minute:
+		return "now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	case d < 60*24*time.Hour:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	case d < 2*365*24*time.Hour:
+		return fmt.Sprintf("%dmo", int(d.Hours()/24/30))
+	}
+	return fmt.Sprintf("%dy", int(d.Hours()/24/365))
+}
@@ -0,0 +1,301 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/coeeter/zap/internal/scan"
+)
+
+const (
+	confirmLargestCount = 5
+	confirmTypeCountAt  = 50
+)
+
+type ConfirmState struct {
+	Paths    []string
+	Nodes    []*PreviewNode
+	Measured map[string]scan.Measurement
+	Sizes    map[string]scan.Usage
+	Apparent bool
+	Warnings []string
+	Typed    string
+}
+
+type confirmSizeMsg struct {
+	path        string
+	measurement scan.Measurement
+}
+
+func (m *Model) EnterConfirm(paths []string) tea.Cmd {
+	paths, nested := pruneNested(paths)
+
+	m.Confirm = &ConfirmState{
+		Paths:    paths,
+		Measured: make(map[string]scan.Measurement),
+		Sizes:    make(map[string]scan.Usage),
+		Apparent: m.WalkOptions.ApparentSize,
+		Warnings: append(confirmWarnings(m.Roots, paths), nested...),
+	}
+	m.Mode = ModeConfirm
+
+	cmds := make([]tea.Cmd, len(paths))
+	for i, p := range paths {
+		cmds[i] = m.sizePath(p)
+	}
+	return tea.Batch(cmds...)
+}
+
+func (m *Model) EnterPreviewConfirm(nodes []*PreviewNode) tea.Cmd {
+	paths := make([]string, len(nodes))
+	for i, node := range nodes {
+		paths[i] = node.Path
+	}
+	cmd := m.EnterConfirm(paths)
+	m.Confirm.Nodes = nodes
+	return cmd
+}
+
+func (m *Model) ExitConfirm() {
+	m.Mode = ModeList
+	if m.Confirm != nil && m.Confirm.Nodes != nil {
+		m.Mode = ModePreview
+	}
+	m.Confirm = nil
+}
+
+func (m Model) sizePath(path string) tea.Cmd {
+	sizes, root := m.SizeCache, rootOf(m.Roots, path)
+	return func() tea.Msg {
+		return confirmSizeMsg{path: path, measurement: sizes.Measure(root, path)}
+	}
+}
+
+// Sizes are folded in selection order rather than arrival order, so a hard
+// link shared by two paths is always charged to the same one.
+func (c *ConfirmState) addMeasurement(path string, m scan.Measurement) {
+	c.Measured[path] = m
+
+	sizer := scan.NewSizer()
+	for _, p := range c.Paths {
+		if m, ok := c.Measured[p]; ok {
+			c.Sizes[p] = sizer.Add(m)
+		}
+	}
+}
+
+func confirmWarnings(roots []string, paths []string) []string {
+	var warnings []string
+	for _, p := range paths {
+		if len(roots) > 0 && !insideAny(roots, p) {
+			warnings = append(warnings, fmt.Sprintf("%s is outside the scan root", p))
+		}
+
+		info, err := os.Lstat(p)
+		if err != nil {
+			continue
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			target, _ := os.Readlink(p)
+			warnings = append(warnings, fmt.Sprintf("%s is a symlink to %s", p, target))
+		}
+		if scan.IsMountPoint(p) {
+			warnings = append(warnings, fmt.Sprintf("%s is a mount point", p))
+		}
+	}
+	return warnings
+}
+
+func insideAny(roots []string, path string) bool {
+	for _, root := range roots {
+		if isInside(root, path) {
+			return true
+		}
+	}
+	return false
+}
+
+func isInside(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+func pruneNested(paths []string) (kept, warnings []string) {
+	for _, p := range paths {
+		parent := ""
+		for _, q := range paths {
+			if strings.HasPrefix(p, q+string(filepath.Separator)) {
+				parent = q
+				break
+			}
+		}
+		if parent == "" {
+			kept = append(kept, p)
+			continue
+		}
+		warnings = append(warnings, fmt.Sprintf("%s is inside %s, which is also selected", p, parent))
+	}
+	return kept, warnings
+}
+
+func (c *ConfirmState) RequireCount() bool {
+	return len(c.Paths) >= confirmTypeCountAt
+}
+
+func (c *ConfirmState) Sizing() bool {
+	return len(c.Sizes) < len(c.Paths)
+}
+
+func (c *ConfirmState) TotalSize() scan.Usage {
+	var total scan.Usage
+	for _, usage := range c.Sizes {
+		total = total.Add(usage)
+	}
+	return total
+}
+
+func (c *ConfirmState) Largest(n int) []string {
+	paths := make([]string, 0, len(c.Sizes))
+	for p := range c.Sizes {
+		paths = append(paths, p)
+	}
+	sort.Slice(paths, func(i, j int) bool {
+		a, b := c.Sizes[paths[i]].Size(c.Apparent), c.Sizes[paths[j]].Size(c.Apparent)
+		if a != b {
+			return a > b
+		}
+		return paths[i] < paths[j]
+	})
+	if len(paths) > n {
+		paths = paths[:n]
+	}
+	return paths
+}
+
+func (m Model) updateConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	c := m.Confirm
+	key := msg.String()
+
+	switch key {
+	case "ctrl+c":
+		m.Quitting = true
+		return m, tea.Quit
+	case "esc", "q":
+		m.ExitConfirm()
+		return m, nil
+	}
+
+	if c.RequireCount() {
+		switch key {
+		case "enter":
+			if c.Typed == strconv.Itoa(len(c.Paths)) {
+				return m.confirmDelete()
+			}
+			c.Typed = ""
+		case "backspace":
+			if len(c.Typed) > 0 {
+				c.Typed = c.Typed[:len(c.Typed)-1]
+			}
+		default:
+			if len(key) == 1 && key[0] >= '0' && key[0] <= '9' {
+				c.Typed += key
+			}
+		}
+		return m, nil
+	}
+
+	switch key {
+	case "y", "Y":
+		return m.confirmDelete()
+	case "n", "N":
+		m.ExitConfirm()
+	}
+
+	return m, nil
+}
+
+func (m Model) confirmDelete() (tea.Model, tea.Cmd) {
+	if nodes := m.Confirm.Nodes; nodes != nil {
+		m.ExitConfirm()
+		return m, m.DeleteMarked(nodes)
+	}
+
+	m.ToDelete = m.Confirm.Paths
+	m.DeleteCalled = true
+	m.Quitting = true
+	return m, tea.Quit
+}
+
+func (m Model) viewConfirm() string {
+	c := m.Confirm
+
+	var b strings.Builder
+
+	noun := "item(s)"
+	if c.Nodes == nil {
+		files := 0
+		for _, p := range c.Paths {
+			if info, err := os.Lstat(p); err == nil && !info.IsDir() {
+				files++
+			}
+		}
+		noun = entryNoun(files, len(c.Paths))
+	}
+	b.WriteString(Title.Render(fmt.Sprintf("Delete %d %s?", len(c.Paths), noun)))
+	b.WriteString("\n")
+
+	usage := c.TotalSize()
+	total := formatSize(usage.Disk) + " on disk"
+	other := formatSize(usage.Apparent) + " apparent"
+	if c.Apparent {
+		total, other = other, total
+	}
+	if c.Sizing() {
+		total = fmt.Sprintf("%s so far (sizing %d/%d…)", total, len(c.Sizes), len(c.Paths))
+	}
+	fmt.Fprintf(&b, "Total size: %s %s\n", Selected.Render(total), Dim.Render("("+other+")"))
+	if usage.Shared > 0 {
+		b.WriteString(Dim.Render(fmt.Sprintf("%s of hard links shared between paths, counted once", formatSize(usage.Shared))))
+		b.WriteString("\n")
+	}
+
+	if largest := c.Largest(confirmLargestCount); len(largest) > 0 {
+		b.WriteString("\n")
+		b.WriteString(Dim.Render("Largest:"))
+		b.WriteString("\n")
+		for _, p := range largest {
+			rel, label := displayPath(m.Roots, rootOf(m.Roots, p), p)
+			line := fmt.Sprintf("  %9s  %s", formatSize(c.Sizes[p].Size(c.Apparent)), rel)
+			b.WriteString(withLabel(line, label) + "\n")
+		}
+	}
+
+	if len(c.Warnings) > 0 {
+		b.WriteString("\n")
+		b.WriteString(Warning.Render("⚠ WARNING"))
+		b.WriteString("\n")
+		for _, w := range c.Warnings {
+			b.WriteString(Error.Render("  " + w))
+			b.WriteString("\n")
+		}
+	}
+
+	if c.RequireCount() {
+		fmt.Fprintf(&b, "\nType %d and press enter to confirm: %s\n", len(c.Paths), Cursor.Render(c.Typed+"_"))
+		b.WriteString(Hint.Render("esc cancel"))
+	} else {
+		b.WriteString(Hint.Render("y confirm • n/esc cancel"))
+	}
+
+	modal := Modal.Render(b.String())
+	if m.Width == 0 || m.Height == 0 {
+		return modal
+	}
+	return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, modal)
+}
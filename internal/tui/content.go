@@ -0,0 +1,258 @@
+package tui
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+const (
+	contentReadLimit = 64 * 1024
+	contentMinWidth  = 80
+	hexBytesPerRow   = 16
+	hexRowWidth      = 10 + hexBytesPerRow*4 + 2
+)
+
+type FileContent struct {
+	Path    string
+	Size    int64
+	Binary  bool
+	Data    []byte
+	Lines   []string
+	Note    string
+	Loading bool
+	Err     error
+}
+
+type contentMsg struct {
+	content *FileContent
+}
+
+func LoadFileContent(path string) *FileContent {
+	c := &FileContent{Path: path}
+
+	f, err := os.Open(path)
+	if err != nil {
+		c.Err = err
+		return c
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		c.Err = err
+		return c
+	}
+	c.Size = info.Size()
+
+	data, err := io.ReadAll(io.LimitReader(f, contentReadLimit))
+	if err != nil {
+		c.Err = err
+		return c
+	}
+	c.Data = data
+	c.Binary = isBinary(data)
+
+	if !c.Binary {
+		text := strings.ReplaceAll(string(data), "\t", "    ")
+		text = strings.ReplaceAll(text, "\r\n", "\n")
+		text = stripControls(text)
+		c.Lines = highlight(strings.TrimSuffix(text, "\n"), filepath.Base(path))
+	}
+
+	return c
+}
+
+func isBinary(data []byte) bool {
+	sample := data
+	if len(sample) > 8000 {
+		sample = sample[:8000]
+	}
+	if bytes.IndexByte(sample, 0) >= 0 {
+		return true
+	}
+	for len(sample) > 0 {
+		r, size := utf8.DecodeRune(sample)
+		if r == utf8.RuneError && size == 1 && len(sample) >= utf8.UTFMax {
+			return true
+		}
+		sample = sample[size:]
+	}
+	return false
+}
+
+// Control characters are shown in caret notation so a file can't send escape
+// sequences to the terminal, e.g. to clear it or write to the clipboard.
+func stripControls(text string) string {
+	var b strings.Builder
+	b.Grow(len(text))
+	for _, r := range text {
+		switch {
+		case r == '\n' || r == '\t':
+			b.WriteRune(r)
+		case r < 0x20:
+			b.WriteByte('^')
+			b.WriteByte(byte(r) + 0x40)
+		case r == 0x7f:
+			b.WriteString("^?")
+		case r >= 0x80 && r < 0xa0:
+			b.WriteRune(utf8.RuneError)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func (m Model) showContentPane() bool {
+	return m.ShowContent && m.Width >= contentMinWidth
+}
+
+func (m *Model) syncContent() tea.Cmd {
+	if m.Mode != ModePreview || !m.ShowContent || m.PreviewCursor >= len(m.PreviewNodes) {
+		m.Content = nil
+		return nil
+	}
+	node := m.PreviewNodes[m.PreviewCursor]
+	if node.IsDir {
+		m.Content = nil
+		return nil
+	}
+	if m.Content != nil && m.Content.Path == node.Path {
+		return nil
+	}
+
+	// Only regular files are opened: reading a named pipe or a device would
+	// block, or never end.
+	if !node.Mode.IsRegular() {
+		m.Content = &FileContent{Path: node.Path, Note: specialKind(node)}
+		return nil
+	}
+
+	m.Content = &FileContent{Path: node.Path, Loading: true}
+	path := node.Path
+	return func() tea.Msg {
+		return contentMsg{content: LoadFileContent(path)}
+	}
+}
+
+func (m *Model) applyContent(msg contentMsg) {
+	if m.Content != nil && m.Content.Path == msg.content.Path {
+		m.Content = msg.content
+	}
+}
+
+func specialKind(node *PreviewNode) string {
+	switch {
+	case node.Mode&fs.ModeSymlink != 0:
+		return fmt.Sprintf("symlink to %s", node.LinkTarget)
+	case node.Mode&fs.ModeNamedPipe != 0:
+		return "named pipe, not opened"
+	case node.Mode&fs.ModeSocket != 0:
+		return "socket, not opened"
+	case node.Mode&fs.ModeDevice != 0:
+		return "device, not opened"
+	}
+	return "special file, not opened"
+}
+
+func (m Model) viewContent(width, height int) []string {
+	if m.PreviewCursor >= len(m.PreviewNodes) {
+		return nil
+	}
+	node := m.PreviewNodes[m.PreviewCursor]
+	if node.IsDir {
+		return []string{Dim.Render(fmt.Sprintf("%s/ (directory)", node.Name))}
+	}
+
+	c := m.Content
+	if c == nil {
+		return nil
+	}
+	if c.Loading {
+		return []string{Dim.Render("loading…")}
+	}
+	if c.Note != "" {
+		return []string{Dim.Render(fmt.Sprintf("%s (%s)", node.Name, c.Note))}
+	}
+	if c.Err != nil {
+		return []string{Error.Render(c.Err.Error())}
+	}
+
+	if c.Binary {
+		perRow := hexBytesPerRow
+		if width < hexRowWidth {
+			perRow /= 2
+		}
+		lines := []string{Dim.Render(fmt.Sprintf("binary, %d bytes", c.Size))}
+		for offset := 0; offset < len(c.Data) && len(lines) < height; offset += perRow {
+			lines = append(lines, hexRow(c.Data, offset, perRow))
+		}
+		return lines
+	}
+
+	header := fmt.Sprintf("%s • %s", node.Name, formatSize(c.Size))
+	if c.Size > contentReadLimit {
+		header += fmt.Sprintf(" • first %s", formatSize(contentReadLimit))
+	}
+	lines := []string{Dim.Render(header)}
+	for i, line := range c.Lines {
+		if len(lines) >= height {
+			break
+		}
+		lines = append(lines, Dim.Render(fmt.Sprintf("%4d ", i+1))+line)
+	}
+	return lines
+}
+
+func hexRow(data []byte, offset, perRow int) string {
+	end := min(offset+perRow, len(data))
+	row := data[offset:end]
+
+	var hex, text strings.Builder
+	for i := 0; i < perRow; i++ {
+		if i < len(row) {
+			fmt.Fprintf(&hex, "%02x ", row[i])
+		} else {
+			hex.WriteString("   ")
+		}
+	}
+	for _, b := range row {
+		if b >= 0x20 && b < 0x7f {
+			text.WriteByte(b)
+		} else {
+			text.WriteByte('.')
+		}
+	}
+
+	return Dim.Render(fmt.Sprintf("%08x  ", offset)) + hex.String() + Dim.Render("|"+text.String()+"|")
+}
+
+func joinColumns(left, right []string, leftWidth, rightWidth int) string {
+	rows := max(len(left), len(right))
+
+	var b strings.Builder
+	for i := 0; i < rows; i++ {
+		var l, r string
+		if i < len(left) {
+			l = ansi.Truncate(left[i], leftWidth, "…")
+		}
+		if i < len(right) {
+			r = ansi.Truncate(right[i], rightWidth, "…")
+		}
+		b.WriteString(l)
+		b.WriteString(strings.Repeat(" ", max(leftWidth-ansi.StringWidth(l), 0)))
+		b.WriteString(Dim.Render(" │ "))
+		b.WriteString(r)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
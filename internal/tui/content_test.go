@@ -0,0 +1,35 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestLoadFileContentStripsControls(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "evil.js")
+	data := "a\x1b]52;c;aGk=\x07b\n\x1b[2Jc\rX\x7f\u009bd\tend\r\n"
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	c := LoadFileContent(path)
+	if c.Binary {
+		t.Fatal("loaded as binary")
+	}
+
+	got := make([]string, len(c.Lines))
+	for i, line := range c.Lines {
+		got[i] = ansi.Strip(line)
+	}
+	want := []string{
+		"a^[]52;c;aGk=^Gb",
+		"^[[2Jc^MX^?�d    end",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("lines = %q, want %q", got, want)
+	}
+}
@@ -6,11 +6,13 @@ import (
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/coeeter/zap/internal/scan"
 )
 
 type DeleteStatus struct {
@@ -18,6 +20,8 @@ type DeleteStatus struct {
 	Status  string
 	Error   error
 	RelPath string
+	Label   string
+	IsFile  bool
 }
 
 type DeleteModel struct {
@@ -25,6 +29,10 @@ type DeleteModel struct {
 	Done      bool
 	StartTime time.Time
 	EndTime   time.Time
+	Before    []scan.Disk
+	After     map[string]scan.Disk
+	Held      int64
+	HeldBy    []string
 	spinner   spinner.Model
 }
 
@@ -39,18 +47,24 @@ type deleteCompleteMsg struct {
 	err   error
 }
 
-func NewDeleteModel(paths []string) DeleteModel {
-	cwd, _ := os.Getwd()
+type diskAfterMsg struct {
+	disks  map[string]scan.Disk
+	held   int64
+	heldBy []string
+}
+
+func NewDeleteModel(paths, roots []string) DeleteModel {
 	items := make([]DeleteStatus, len(paths))
 	for i, p := range paths {
-		relPath, err := filepath.Rel(cwd, p)
-		if err != nil {
-			relPath = p
-		}
+		relPath, label := displayPath(roots, rootOf(roots, p), p)
 		items[i] = DeleteStatus{
 			Path:    p,
 			Status:  "pending",
 			RelPath: relPath,
+			Label:   label,
+		}
+		if info, err := os.Lstat(p); err == nil && !info.IsDir() {
+			items[i].IsFile = true
 		}
 	}
 
@@ -61,10 +75,26 @@ func NewDeleteModel(paths []string) DeleteModel {
 	return DeleteModel{
 		Items:     items,
 		StartTime: time.Now(),
+		Before:    disksOf(paths),
 		spinner:   s,
 	}
 }
 
+func disksOf(paths []string) []scan.Disk {
+	var disks []scan.Disk
+	seen := make(map[string]bool)
+	for _, p := range paths {
+		d, err := scan.DiskOf(filepath.Dir(realPath(p)))
+		if err != nil || seen[d.Mount] {
+			continue
+		}
+		seen[d.Mount] = true
+		disks = append(disks, d)
+	}
+	sort.Slice(disks, func(i, j int) bool { return disks[i].Mount < disks[j].Mount })
+	return disks
+}
+
 func (m DeleteModel) Init() tea.Cmd {
 	cmds := []tea.Cmd{m.spinner.Tick}
 	for i := range m.Items {
@@ -75,18 +105,25 @@ func (m DeleteModel) Init() tea.Cmd {
 
 func (m DeleteModel) startDelete(index int) tea.Cmd {
 	return func() tea.Msg {
-		path := m.Items[index].Path
-		var err error
+		return deleteCompleteMsg{index: index, err: removePath(m.Items[index].Path)}
+	}
+}
 
-		if runtime.GOOS == "windows" {
-			err = os.RemoveAll(path)
-		} else {
-			cmd := exec.Command("rm", "-rf", path)
-			err = cmd.Run()
-		}
+// Never delete through a symlinked parent: resolve it so the removal acts on
+// the real location.
+func realPath(path string) string {
+	if dir, err := filepath.EvalSymlinks(filepath.Dir(path)); err == nil {
+		return filepath.Join(dir, filepath.Base(path))
+	}
+	return path
+}
 
-		return deleteCompleteMsg{index: index, err: err}
+func removePath(path string) error {
+	path = realPath(path)
+	if runtime.GOOS == "windows" {
+		return os.RemoveAll(path)
 	}
+	return exec.Command("rm", "-rf", path).Run()
 }
 
 func (m DeleteModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -116,15 +153,80 @@ func (m DeleteModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 		if allDone {
-			m.Done = true
 			m.EndTime = time.Now()
-			return m, tea.Quit
+			return m, m.measureAfter()
 		}
+
+	case diskAfterMsg:
+		m.After = msg.disks
+		m.Held = msg.held
+		m.HeldBy = msg.heldBy
+		m.Done = true
+		return m, tea.Quit
 	}
 
 	return m, nil
 }
 
+func (m DeleteModel) measureAfter() tea.Cmd {
+	paths := make([]string, len(m.Items))
+	for i, item := range m.Items {
+		paths[i] = realPath(item.Path)
+	}
+	before := m.Before
+
+	return func() tea.Msg {
+		disks := make(map[string]scan.Disk)
+		for _, d := range before {
+			if after, err := scan.DiskOf(d.Mount); err == nil {
+				disks[d.Mount] = after
+			}
+		}
+		held, heldBy := scan.HeldOpen(paths)
+		return diskAfterMsg{disks: disks, held: held, heldBy: heldBy}
+	}
+}
+
+func (m DeleteModel) viewHeader() string {
+	if len(m.Before) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString(Dim.Render("Before"))
+	b.WriteString("\n")
+	for _, d := range m.Before {
+		fmt.Fprintf(&b, "  %s  %s free of %s\n", Dim.Render(shortenHome(d.Mount)),
+			formatSize(int64(d.Free)), formatSize(int64(d.Total)))
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+func (m DeleteModel) viewAfter() string {
+	var b strings.Builder
+	for _, before := range m.Before {
+		after, ok := m.After[before.Mount]
+		if !ok {
+			continue
+		}
+
+		delta := int64(after.Free) - int64(before.Free)
+		sign := "+"
+		if delta < 0 {
+			sign, delta = "-", -delta
+		}
+		fmt.Fprintf(&b, "  %s  %s free of %s (%s)\n", Dim.Render(shortenHome(after.Mount)),
+			Selected.Render(formatSize(int64(after.Free))), formatSize(int64(after.Total)), sign+formatSize(delta))
+	}
+	if m.Held > 0 {
+		b.WriteString(Warning.Render(fmt.Sprintf("  %s is still held open by %s (freed once they close it)",
+			formatSize(m.Held), strings.Join(m.HeldBy, ", "))))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
 func (m DeleteModel) View() string {
 	var b strings.Builder
 
@@ -144,36 +246,51 @@ func (m DeleteModel) View() string {
 
 		b.WriteString(Title.Render("Deletion complete"))
 		b.WriteString("\n\n")
+		b.WriteString(m.viewHeader())
 
 		for _, item := range m.Items {
 			if item.Status == "done" {
-				b.WriteString(Success.Render(fmt.Sprintf("  ✓ %s", item.RelPath)))
+				b.WriteString(withLabel(Success.Render(fmt.Sprintf("  ✓ %s", item.RelPath)), item.Label))
 			} else {
-				b.WriteString(Error.Render(fmt.Sprintf("  ✗ %s", item.RelPath)))
+				b.WriteString(withLabel(Error.Render(fmt.Sprintf("  ✗ %s", item.RelPath)), item.Label))
 			}
 			b.WriteString("\n")
 		}
 
 		b.WriteString("\n")
-		summary := fmt.Sprintf("Deleted %d/%d folder(s) in %v", deleted, len(m.Items), elapsed)
+		files := 0
+		for _, item := range m.Items {
+			if item.IsFile {
+				files++
+			}
+		}
+		summary := fmt.Sprintf("Deleted %d/%d %s in %v", deleted, len(m.Items), entryNoun(files, len(m.Items)), elapsed)
 		if len(errors) > 0 {
 			b.WriteString(Error.Render(summary))
 		} else {
 			b.WriteString(Success.Render(summary))
 		}
+		if after := m.viewAfter(); after != "" {
+			b.WriteString("\n\n")
+			b.WriteString(Dim.Render("After"))
+			b.WriteString("\n")
+			b.WriteString(after)
+		}
 	} else {
 		b.WriteString(Title.Render("Deleting..."))
 		b.WriteString("\n\n")
+		b.WriteString(m.viewHeader())
 
 		for _, item := range m.Items {
 			switch item.Status {
 			case "pending", "deleting":
-				fmt.Fprintf(&b, "  %s %s\n", m.spinner.View(), item.RelPath)
+				b.WriteString(withLabel(fmt.Sprintf("  %s %s", m.spinner.View(), item.RelPath), item.Label))
+				b.WriteString("\n")
 			case "done":
-				b.WriteString(Success.Render(fmt.Sprintf("  ✓ %s", item.RelPath)))
+				b.WriteString(withLabel(Success.Render(fmt.Sprintf("  ✓ %s", item.RelPath)), item.Label))
 				b.WriteString("\n")
 			case "error":
-				b.WriteString(Error.Render(fmt.Sprintf("  ✗ %s", item.RelPath)))
+				b.WriteString(withLabel(Error.Render(fmt.Sprintf("  ✗ %s", item.RelPath)), item.Label))
 				b.WriteString("\n")
 			}
 		}
@@ -182,12 +299,12 @@ func (m DeleteModel) View() string {
 	return b.String()
 }
 
-func RunDelete(paths []string) (DeleteResult, error) {
+func RunDelete(paths, roots []string) (DeleteResult, error) {
 	if len(paths) == 0 {
 		return DeleteResult{}, nil
 	}
 
-	model := NewDeleteModel(paths)
+	model := NewDeleteModel(paths, roots)
 
 	finalModel, err := tea.NewProgram(model).Run()
 	if err != nil {
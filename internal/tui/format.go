@@ -0,0 +1,96 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/coeeter/zap/internal/scan"
+)
+
+func formatSize(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
+
+func entryNoun(files, total int) string {
+	switch {
+	case files == 0:
+		return "folder(s)"
+	case files == total:
+		return "file(s)"
+	}
+	return "item(s)"
+}
+
+func (m Model) itemNoun() string {
+	files := 0
+	for _, item := range m.Items {
+		if item.Result.IsFile {
+			files++
+		}
+	}
+	return entryNoun(files, len(m.Items))
+}
+
+func (m Model) displayPath(r scan.Result) (rel, label string) {
+	return displayPath(m.Roots, r.Root, r.Path)
+}
+
+func displayPath(roots []string, root, path string) (rel, label string) {
+	cwd, _ := os.Getwd()
+
+	if root == "" {
+		root = cwd
+	}
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return path, ""
+	}
+	if len(roots) > 1 || root != cwd {
+		label = shortenHome(root)
+	}
+	return rel, label
+}
+
+// Paths outside every root, such as the target of a followed link, are shown
+// relative to the working directory.
+func rootOf(roots []string, path string) string {
+	root := ""
+	for _, r := range roots {
+		if (path == r || strings.HasPrefix(path, r+string(filepath.Separator))) && len(r) > len(root) {
+			root = r
+		}
+	}
+	return root
+}
+
+func withLabel(s, label string) string {
+	if label == "" {
+		return s
+	}
+	return s + Dim.Render("  "+label)
+}
+
+func shortenHome(path string) string {
+	home, err := os.UserHomeDir()
+	if err != nil || home == "" {
+		return path
+	}
+	if path == home {
+		return "~"
+	}
+	if strings.HasPrefix(path, home+string(filepath.Separator)) {
+		return "~" + path[len(home):]
+	}
+	return path
+}
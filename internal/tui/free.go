@@ -0,0 +1,112 @@
+package tui
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/coeeter/zap/internal/scan"
+)
+
+func (m *Model) selectToFree() {
+	var order []int
+	for i, item := range m.Items {
+		if item.Parent < 0 {
+			order = append(order, i)
+		}
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		x, y := m.Items[order[a]].Result, m.Items[order[b]].Result
+		if m.FreeOldest && !x.Newest.Equal(y.Newest) {
+			return x.Newest.Before(y.Newest)
+		}
+		return x.Size > y.Size
+	})
+
+	var total int64
+	for _, i := range order {
+		if total >= m.Free {
+			break
+		}
+		if m.Items[i].Result.Size == 0 {
+			continue
+		}
+		m.Items[i].Selected = true
+		total += m.Items[i].Result.Size
+	}
+
+	for i, item := range m.Items {
+		if item.Selected {
+			m.Cursor = i
+			break
+		}
+	}
+	if total < m.Free {
+		m.Status = Warning.Render(fmt.Sprintf("only %s found, short of the %s target", formatSize(total), formatSize(m.Free)))
+	}
+}
+
+func (m Model) SelectedSize() int64 {
+	var total int64
+	for i, item := range m.Items {
+		if item.Selected && m.selectedAncestor(i) < 0 {
+			total += item.Result.Size
+		}
+	}
+	return total
+}
+
+func (m *Model) loadDisks() {
+	if m.Disks == nil {
+		m.Disks = make(map[string]scan.Disk)
+	}
+	for i := range m.Items {
+		item := &m.Items[i]
+		if item.mount != "" {
+			continue
+		}
+		path := item.Result.Target()
+		if item.Result.IsFile {
+			path = filepath.Dir(path)
+		}
+		if d, err := scan.DiskOf(path); err == nil {
+			item.mount = d.Mount
+			m.Disks[d.Mount] = d
+		}
+	}
+}
+
+func (m Model) viewDisks() []string {
+	freed := make(map[string]int64)
+	for i, item := range m.Items {
+		if item.Selected && m.selectedAncestor(i) < 0 {
+			freed[item.mount] += item.Result.Size
+		}
+	}
+
+	mounts := make([]string, 0, len(m.Disks))
+	for mount := range m.Disks {
+		mounts = append(mounts, mount)
+	}
+	sort.Strings(mounts)
+
+	lines := make([]string, len(mounts))
+	for i, mount := range mounts {
+		d := m.Disks[mount]
+		used := d.Used()
+		after := used - min(uint64(freed[mount]), used)
+		lines[i] = fmt.Sprintf("  %s  %s of %s used (%s) → %s (%s), %s free",
+			Dim.Render(shortenHome(mount)),
+			formatSize(int64(used)), formatSize(int64(d.Total)), percent(used, d.Total),
+			formatSize(int64(after)), percent(after, d.Total),
+			Selected.Render(formatSize(int64(d.Total-after))))
+	}
+	return lines
+}
+
+func percent(n, total uint64) string {
+	if total == 0 {
+		return "–"
+	}
+	return fmt.Sprintf("%.0f%%", float64(n)/float64(total)*100)
+}
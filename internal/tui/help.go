@@ -0,0 +1,34 @@
+package tui
+
+import (
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func newHelp() help.Model {
+	h := help.New()
+	h.Styles.ShortKey = Dim
+	h.Styles.ShortDesc = Dim
+	h.Styles.ShortSeparator = Dim
+	h.Styles.FullKey = Selected
+	h.Styles.FullDesc = File
+	h.Styles.FullSeparator = Dim
+	return h
+}
+
+func (m Model) viewHelp() string {
+	var keys help.KeyMap = listHelp{m.Keys}
+	if m.Mode == ModePreview {
+		keys = previewHelp{m.Keys}
+	}
+
+	content := Title.Render("Keybindings") + "\n" +
+		m.Help.FullHelpView(keys.FullHelp()) + "\n" +
+		Hint.Render("press any key to close")
+
+	modal := Modal.Render(content)
+	if m.Width == 0 || m.Height == 0 {
+		return modal
+	}
+	return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, modal)
+}
@@ -0,0 +1,66 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func highlight(text, name string) []string {
+	lines := strings.Split(text, "\n")
+	plain := func() []string {
+		for i, line := range lines {
+			lines[i] = File.Render(line)
+		}
+		return lines
+	}
+
+	lexer := lexers.Match(name)
+	if lexer == nil {
+		return plain()
+	}
+	tokens, err := chroma.Coalesce(lexer).Tokenise(nil, text)
+	if err != nil {
+		return plain()
+	}
+
+	var b strings.Builder
+	for token := tokens(); token != chroma.EOF; token = tokens() {
+		style := tokenStyle(token.Type)
+		// Styles are applied per line so a multi-line comment or string
+		// stays coloured when lines are truncated or scrolled on their own.
+		for i, part := range strings.Split(token.Value, "\n") {
+			if i > 0 {
+				b.WriteString("\n")
+			}
+			if part != "" {
+				b.WriteString(style.Render(part))
+			}
+		}
+	}
+
+	// Lexers may add a trailing newline; keep one output line per input line.
+	out := strings.Split(b.String(), "\n")
+	if len(out) < len(lines) {
+		return plain()
+	}
+	return out[:len(lines)]
+}
+
+func tokenStyle(t chroma.TokenType) lipgloss.Style {
+	switch {
+	case t == chroma.CommentPreproc || t.Category() == chroma.Keyword:
+		return Dir
+	case t == chroma.CommentPreprocFile:
+		return Success
+	case t.Category() == chroma.Comment:
+		return Dim
+	case t.SubCategory() == chroma.LiteralString:
+		return Success
+	case t.SubCategory() == chroma.LiteralNumber:
+		return lipgloss.NewStyle().Foreground(Accent)
+	}
+	return File
+}
@@ -2,56 +2,180 @@ package tui
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/coeeter/zap/internal/history"
+	"github.com/coeeter/zap/internal/scan"
+)
+
+const (
+	presetRowWidth = 40
+	dirNameDepth   = 4
 )
 
 type InputModel struct {
-	textInput textinput.Model
-	quitting  bool
-	submitted bool
+	textInput    textinput.Model
+	roots        []string
+	history      []string
+	historyIndex int
+	draft        string
+	dirNames     []string
+	mode         scan.MatchMode
+	presets      map[string]bool
+	presetFocus  bool
+	presetCursor int
+	quitting     bool
+	submitted    bool
 }
 
 type InputResult struct {
-	Value     string
+	Query     scan.Query
 	Submitted bool
 }
 
-func NewInputModel(defaultValue string) InputModel {
+type dirNamesMsg struct {
+	names []string
+}
+
+func NewInputModel(defaultValue string, roots []string, q scan.Query, hist []string) InputModel {
 	ti := textinput.New()
 	ti.Placeholder = defaultValue
 	ti.Focus()
 	ti.CharLimit = 256
 	ti.Width = 40
-	ti.PromptStyle = lipgloss.NewStyle().Foreground(Pink)
-	ti.TextStyle = lipgloss.NewStyle().Foreground(White)
+	ti.PromptStyle = lipgloss.NewStyle().Foreground(Accent)
+	ti.TextStyle = lipgloss.NewStyle().Foreground(Text)
+	ti.CompletionStyle = Dim
+	ti.ShowSuggestions = true
+	ti.KeyMap.NextSuggestion.SetKeys("ctrl+n")
+	ti.KeyMap.PrevSuggestion.SetKeys("ctrl+p")
+	ti.SetValue(q.Name)
 
-	return InputModel{
-		textInput: ti,
+	presets := make(map[string]bool)
+	for _, p := range q.Presets {
+		presets[p] = true
 	}
+
+	m := InputModel{
+		textInput:    ti,
+		roots:        roots,
+		history:      hist,
+		historyIndex: -1,
+		mode:         q.Mode,
+		presets:      presets,
+	}
+	m.updateSuggestions()
+	return m
 }
 
 func (m InputModel) Init() tea.Cmd {
-	return textinput.Blink
+	return tea.Batch(textinput.Blink, loadDirNames(m.roots))
+}
+
+func loadDirNames(roots []string) tea.Cmd {
+	return func() tea.Msg {
+		var names []string
+		for _, root := range roots {
+			names = append(names, scan.DirNames(root, dirNameDepth)...)
+		}
+		return dirNamesMsg{names: names}
+	}
+}
+
+func (m *InputModel) updateSuggestions() {
+	var suggestions []string
+	seen := make(map[string]bool)
+	add := func(names ...string) {
+		for _, name := range names {
+			if !seen[name] {
+				seen[name] = true
+				suggestions = append(suggestions, name)
+			}
+		}
+	}
+
+	add(m.history...)
+	for _, name := range scan.PresetNames() {
+		add(scan.Presets[name]...)
+	}
+	add(m.dirNames...)
+
+	m.textInput.SetSuggestions(suggestions)
+}
+
+func (m *InputModel) recall(step int) {
+	next := m.historyIndex + step
+	if next < -1 || next >= len(m.history) {
+		return
+	}
+	if m.historyIndex == -1 {
+		m.draft = m.textInput.Value()
+	}
+
+	m.historyIndex = next
+	if next == -1 {
+		m.textInput.SetValue(m.draft)
+	} else {
+		m.textInput.SetValue(m.history[next])
+	}
+	m.textInput.CursorEnd()
 }
 
 func (m InputModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	switch msg := msg.(type) {
-	case tea.KeyMsg:
+	m, cmd := m.update(msg)
+	if m.quitting {
+		return m, tea.Quit
+	}
+	return m, cmd
+}
+
+func (m InputModel) update(msg tea.Msg) (InputModel, tea.Cmd) {
+	if msg, ok := msg.(dirNamesMsg); ok {
+		m.dirNames = msg.names
+		m.updateSuggestions()
+		return m, nil
+	}
+
+	if msg, ok := msg.(tea.KeyMsg); ok {
 		switch msg.String() {
 		case "enter":
-			if m.textInput.Value() == "" {
+			if m.textInput.Value() == "" && len(m.presets) == 0 {
 				m.textInput.SetValue(m.textInput.Placeholder)
 			}
 			m.submitted = true
 			m.quitting = true
-			return m, tea.Quit
+			return m, nil
 		case "esc", "ctrl+c":
 			m.quitting = true
-			return m, tea.Quit
+			return m, nil
+		case "ctrl+g":
+			m.mode = (m.mode + 1) % (scan.MatchRegex + 1)
+			return m, nil
+		case "ctrl+t":
+			m.presetFocus = !m.presetFocus
+			if m.presetFocus {
+				m.textInput.Blur()
+				return m, nil
+			}
+			return m, m.textInput.Focus()
+		}
+
+		if m.presetFocus {
+			return m.updatePresets(msg), nil
+		}
+
+		switch msg.String() {
+		case "up":
+			m.recall(1)
+			return m, nil
+		case "down":
+			m.recall(-1)
+			return m, nil
 		}
+		m.historyIndex = -1
 	}
 
 	var cmd tea.Cmd
@@ -59,18 +183,94 @@ func (m InputModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+func (m InputModel) updatePresets(msg tea.KeyMsg) InputModel {
+	names := scan.PresetNames()
+	switch msg.String() {
+	case "left", "h", "shift+tab":
+		if m.presetCursor > 0 {
+			m.presetCursor--
+		}
+	case "right", "l", "tab":
+		if m.presetCursor < len(names)-1 {
+			m.presetCursor++
+		}
+	case " ", "x":
+		name := names[m.presetCursor]
+		if m.presets[name] {
+			delete(m.presets, name)
+		} else {
+			m.presets[name] = true
+		}
+	}
+	return m
+}
+
+func (m InputModel) Query() scan.Query {
+	q := scan.Query{Name: strings.TrimSpace(m.textInput.Value()), Mode: m.mode}
+	for _, name := range scan.PresetNames() {
+		if m.presets[name] {
+			q.Presets = append(q.Presets, name)
+		}
+	}
+	return q
+}
+
 func (m InputModel) View() string {
 	if m.quitting {
 		return ""
 	}
-	title := Title.Render("Enter folder name to search:")
-	input := m.textInput.View()
-	hint := Hint.Render("enter to search • esc to quit")
-	return fmt.Sprintf("%s\n%s\n%s", title, input, hint)
+	return m.view("esc quit")
+}
+
+func (m InputModel) view(cancel string) string {
+	var b strings.Builder
+
+	b.WriteString(Title.Render("Enter folder name to search:"))
+	b.WriteString("\n")
+	b.WriteString(m.textInput.View())
+	b.WriteString("\n\n")
+
+	modes := make([]string, 0, 3)
+	for mode := scan.MatchExact; mode <= scan.MatchRegex; mode++ {
+		if mode == m.mode {
+			modes = append(modes, Selected.Render(mode.String()))
+		} else {
+			modes = append(modes, Dim.Render(mode.String()))
+		}
+	}
+	fmt.Fprintf(&b, "%s %s\n", Dim.Render("match:  "), strings.Join(modes, " / "))
+
+	b.WriteString(Dim.Render("presets:"))
+	width := 0
+	for i, name := range scan.PresetNames() {
+		if width > presetRowWidth {
+			b.WriteString("\n" + strings.Repeat(" ", len("presets:")))
+			width = 0
+		}
+		width += len(name) + 3
+
+		label := "○ " + name
+		if m.presets[name] {
+			label = Selected.Render("● " + name)
+		}
+		if m.presetFocus && i == m.presetCursor {
+			label = Cursor.Render(label)
+		}
+		b.WriteString(" " + label)
+	}
+	b.WriteString("\n")
+
+	hint := "enter search • ↑/↓ history • tab complete\nctrl+g match mode • ctrl+t presets • " + cancel
+	if m.presetFocus {
+		hint = "←/→ move • space toggle • ctrl+t name • enter search • " + cancel
+	}
+	b.WriteString(Hint.Render(hint))
+	return b.String()
 }
 
-func RunInput(defaultValue string) (InputResult, error) {
-	model := NewInputModel(defaultValue)
+func RunInput(defaultValue string, roots []string, q scan.Query) (InputResult, error) {
+	hist, _ := history.Load()
+	model := NewInputModel(defaultValue, roots, q, hist)
 
 	p := tea.NewProgram(model)
 	finalModel, err := p.Run()
@@ -80,7 +280,7 @@ func RunInput(defaultValue string) (InputResult, error) {
 
 	m := finalModel.(InputModel)
 	return InputResult{
-		Value:     m.textInput.Value(),
+		Query:     m.Query(),
 		Submitted: m.submitted,
 	}, nil
 }
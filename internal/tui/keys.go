@@ -0,0 +1,259 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type KeyMap struct {
+	Up          key.Binding
+	Down        key.Binding
+	Top         key.Binding
+	Bottom      key.Binding
+	Toggle      key.Binding
+	SelectAll   key.Binding
+	DeselectAll key.Binding
+	Invert      key.Binding
+	Preview     key.Binding
+	Delete      key.Binding
+	Quit        key.Binding
+	Help        key.Binding
+	Rescan      key.Binding
+	NewSearch   key.Binding
+	Edit        key.Binding
+	Page        key.Binding
+	Shell       key.Binding
+	Open        key.Binding
+
+	Expand       key.Binding
+	Collapse     key.Binding
+	NextFolder   key.Binding
+	PrevFolder   key.Binding
+	Contents     key.Binding
+	Sort         key.Binding
+	Details      key.Binding
+	LoadMore     key.Binding
+	RaiseLimits  key.Binding
+	Mark         key.Binding
+	DeleteMarked key.Binding
+	Search       key.Binding
+	NextMatch    key.Binding
+	PrevMatch    key.Binding
+	Back         key.Binding
+}
+
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		Up:          key.NewBinding(key.WithKeys("up", "k", "ctrl+p"), key.WithHelp("↑/k", "up")),
+		Down:        key.NewBinding(key.WithKeys("down", "j", "ctrl+n"), key.WithHelp("↓/j", "down")),
+		Top:         key.NewBinding(key.WithKeys("g g", "home"), key.WithHelp("gg/home", "go to top")),
+		Bottom:      key.NewBinding(key.WithKeys("G", "end"), key.WithHelp("G/end", "go to bottom")),
+		Toggle:      key.NewBinding(key.WithKeys(" "), key.WithHelp("space", "select")),
+		SelectAll:   key.NewBinding(key.WithKeys("a"), key.WithHelp("a", "all")),
+		DeselectAll: key.NewBinding(key.WithKeys("A"), key.WithHelp("A", "none")),
+		Invert:      key.NewBinding(key.WithKeys("i"), key.WithHelp("i", "invert")),
+		Preview:     key.NewBinding(key.WithKeys("v", "l", "tab"), key.WithHelp("v", "preview")),
+		Delete:      key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "delete")),
+		Quit:        key.NewBinding(key.WithKeys("q", "esc", "ctrl+c"), key.WithHelp("q", "quit")),
+		Help:        key.NewBinding(key.WithKeys("?"), key.WithHelp("?", "help")),
+		Rescan:      key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "rescan")),
+		NewSearch:   key.NewBinding(key.WithKeys("/"), key.WithHelp("/", "new search")),
+		Edit:        key.NewBinding(key.WithKeys("e"), key.WithHelp("e", "open in $EDITOR")),
+		Page:        key.NewBinding(key.WithKeys("p"), key.WithHelp("p", "open in $PAGER")),
+		Shell:       key.NewBinding(key.WithKeys("S"), key.WithHelp("S", "shell here")),
+		Open:        key.NewBinding(key.WithKeys("o"), key.WithHelp("o", "open externally")),
+
+		Expand:       key.NewBinding(key.WithKeys("enter", "l"), key.WithHelp("enter/l", "expand")),
+		Collapse:     key.NewBinding(key.WithKeys("h"), key.WithHelp("h", "collapse")),
+		NextFolder:   key.NewBinding(key.WithKeys("]", "tab"), key.WithHelp("]", "next folder")),
+		PrevFolder:   key.NewBinding(key.WithKeys("[", "shift+tab"), key.WithHelp("[", "prev folder")),
+		Contents:     key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "contents")),
+		Sort:         key.NewBinding(key.WithKeys("s"), key.WithHelp("s", "sort by size")),
+		Details:      key.NewBinding(key.WithKeys("i"), key.WithHelp("i", "details")),
+		LoadMore:     key.NewBinding(key.WithKeys("m"), key.WithHelp("m", "load more")),
+		RaiseLimits:  key.NewBinding(key.WithKeys("+"), key.WithHelp("+", "raise limits")),
+		Mark:         key.NewBinding(key.WithKeys(" "), key.WithHelp("space", "mark")),
+		DeleteMarked: key.NewBinding(key.WithKeys("x"), key.WithHelp("x", "delete marked")),
+		Search:       key.NewBinding(key.WithKeys("/"), key.WithHelp("/", "search")),
+		NextMatch:    key.NewBinding(key.WithKeys("n"), key.WithHelp("n", "next match")),
+		PrevMatch:    key.NewBinding(key.WithKeys("N"), key.WithHelp("N", "prev match")),
+		Back:         key.NewBinding(key.WithKeys("q", "esc"), key.WithHelp("q", "back")),
+	}
+}
+
+func (k *KeyMap) bindings() map[string]*key.Binding {
+	return map[string]*key.Binding{
+		"up":            &k.Up,
+		"down":          &k.Down,
+		"top":           &k.Top,
+		"bottom":        &k.Bottom,
+		"toggle":        &k.Toggle,
+		"select_all":    &k.SelectAll,
+		"deselect_all":  &k.DeselectAll,
+		"invert":        &k.Invert,
+		"preview":       &k.Preview,
+		"delete":        &k.Delete,
+		"quit":          &k.Quit,
+		"help":          &k.Help,
+		"rescan":        &k.Rescan,
+		"new_search":    &k.NewSearch,
+		"edit":          &k.Edit,
+		"page":          &k.Page,
+		"shell":         &k.Shell,
+		"open":          &k.Open,
+		"expand":        &k.Expand,
+		"collapse":      &k.Collapse,
+		"next_folder":   &k.NextFolder,
+		"prev_folder":   &k.PrevFolder,
+		"contents":      &k.Contents,
+		"sort":          &k.Sort,
+		"details":       &k.Details,
+		"load_more":     &k.LoadMore,
+		"raise_limits":  &k.RaiseLimits,
+		"mark":          &k.Mark,
+		"delete_marked": &k.DeleteMarked,
+		"search":        &k.Search,
+		"next_match":    &k.NextMatch,
+		"prev_match":    &k.PrevMatch,
+		"back":          &k.Back,
+	}
+}
+
+func (k *KeyMap) Apply(overrides map[string][]string) error {
+	bindings := k.bindings()
+
+	names := make([]string, 0, len(overrides))
+	for name := range overrides {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		b, ok := bindings[name]
+		if !ok {
+			return fmt.Errorf("unknown key action %q", name)
+		}
+
+		keys := make([]string, len(overrides[name]))
+		for i, k := range overrides[name] {
+			keys[i] = normalizeKey(k)
+		}
+		if len(keys) == 0 {
+			b.Unbind()
+			continue
+		}
+
+		b.SetKeys(keys...)
+		b.SetHelp(keyLabel(keys[0]), b.Help().Desc)
+	}
+
+	return nil
+}
+
+func normalizeKey(k string) string {
+	parts := strings.Fields(k)
+	for i, p := range parts {
+		if p == "space" {
+			parts[i] = " "
+		}
+	}
+	if len(parts) == 0 {
+		return k
+	}
+	return strings.Join(parts, " ")
+}
+
+func keyLabel(k string) string {
+	switch k {
+	case " ":
+		return "space"
+	case "up":
+		return "↑"
+	case "down":
+		return "↓"
+	}
+	return strings.ReplaceAll(k, " ", "")
+}
+
+func pressed(prev string, msg tea.KeyMsg, b key.Binding) bool {
+	if !b.Enabled() {
+		return false
+	}
+	k := msg.String()
+	for _, want := range b.Keys() {
+		if want == k || (prev != "" && want == prev+" "+k) {
+			return true
+		}
+	}
+	return false
+}
+
+func startsSequence(msg tea.KeyMsg, bindings ...key.Binding) bool {
+	prefix := msg.String() + " "
+	for _, b := range bindings {
+		if !b.Enabled() {
+			continue
+		}
+		for _, want := range b.Keys() {
+			if strings.HasPrefix(want, prefix) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (k KeyMap) listBindings() []key.Binding {
+	return []key.Binding{
+		k.Quit, k.Help, k.Up, k.Down, k.Top, k.Bottom, k.Toggle,
+		k.SelectAll, k.DeselectAll, k.Invert, k.Preview, k.Delete, k.Rescan, k.NewSearch,
+		k.Edit, k.Page, k.Shell, k.Open,
+	}
+}
+
+func (k KeyMap) previewBindings() []key.Binding {
+	return []key.Binding{
+		k.Back, k.Help, k.Up, k.Down, k.Top, k.Bottom,
+		k.Expand, k.Collapse, k.NextFolder, k.PrevFolder, k.Contents, k.Sort, k.Details,
+		k.LoadMore, k.RaiseLimits, k.Mark, k.DeleteMarked,
+		k.Search, k.NextMatch, k.PrevMatch,
+		k.Edit, k.Page, k.Shell, k.Open,
+	}
+}
+
+type listHelp struct{ KeyMap }
+
+func (k listHelp) ShortHelp() []key.Binding {
+	return []key.Binding{k.Up, k.Down, k.Toggle, k.Preview, k.Delete, k.Quit, k.Help}
+}
+
+func (k listHelp) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Up, k.Down, k.Top, k.Bottom},
+		{k.Toggle, k.SelectAll, k.DeselectAll, k.Invert},
+		{k.Preview, k.Delete, k.Rescan, k.NewSearch, k.Help, k.Quit},
+		{k.Edit, k.Page, k.Shell, k.Open},
+	}
+}
+
+type previewHelp struct{ KeyMap }
+
+func (k previewHelp) ShortHelp() []key.Binding {
+	return []key.Binding{k.Up, k.Down, k.Expand, k.Collapse, k.Search, k.NextFolder, k.Back, k.Help}
+}
+
+func (k previewHelp) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Up, k.Down, k.Top, k.Bottom},
+		{k.Expand, k.Collapse, k.Contents, k.Sort, k.Details},
+		{k.LoadMore, k.RaiseLimits},
+		{k.Mark, k.DeleteMarked},
+		{k.Search, k.NextMatch, k.PrevMatch},
+		{k.Edit, k.Page, k.Shell, k.Open},
+		{k.NextFolder, k.PrevFolder, k.Help, k.Back},
+	}
+}
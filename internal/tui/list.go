@@ -2,10 +2,12 @@ package tui
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/coeeter/zap/internal/scan"
 )
@@ -15,26 +17,75 @@ type Mode int
 const (
 	ModeList Mode = iota
 	ModePreview
+	ModeConfirm
 )
 
 type Item struct {
 	Result   scan.Result
 	Selected bool
+	Parent   int
+	Level    int
+
+	aging bool
+	mount string
 }
 
 type Model struct {
 	Mode          Mode
+	Roots         []string
 	Items         []Item
 	Cursor        int
+	ListOffset    int
 	Width         int
 	Height        int
 	PreviewRoot   *PreviewNode
 	PreviewCursor int
-	PreviewNodes  []*PreviewNode
-	LastKey       string
-	Quitting      bool
-	ToDelete      []string
-	DeleteCalled  bool
+	PreviewOffset int
+	ShowContent   bool
+	SortBySize    bool
+	ShowDetails   bool
+
+	PreviewDepth     int
+	PreviewItems     int
+	PreviewTruncated bool
+	PreviewDeletion  *PreviewDeletion
+	PreviewSearch    *PreviewSearch
+	Searching        bool
+	SearchInput      textinput.Model
+	Content          *FileContent
+	PreviewNodes     []*PreviewNode
+	Confirm          *ConfirmState
+	Keys             KeyMap
+	Help             help.Model
+	ShowHelp         bool
+	Query            scan.Query
+	WalkOptions      scan.Options
+	SizeCache        *scan.SizeCache
+	Scan             func(scan.Query) ([]scan.Result, error)
+	Scanning         bool
+	Free             int64
+	FreeOldest       bool
+	Disks            map[string]scan.Disk
+	Prompt           *InputModel
+	Status           string
+	LastKey          string
+	LastClickRow     int
+	LastClickAt      time.Time
+	Quitting         bool
+	ToDelete         []string
+	DeleteCalled     bool
+}
+
+type Options struct {
+	Roots        []string
+	Keys         KeyMap
+	PreviewDepth int
+	PreviewItems int
+	Query        scan.Query
+	WalkOptions  scan.Options
+	Scan         func(scan.Query) ([]scan.Result, error)
+	Free         int64
+	FreeOldest   bool
 }
 
 type Result struct {
@@ -42,16 +93,48 @@ type Result struct {
 	DeleteConfirmed bool
 }
 
-func NewModel(results []scan.Result) Model {
+func NewModel(results []scan.Result, opts Options) Model {
+	if opts.PreviewDepth <= 0 {
+		opts.PreviewDepth = DefaultPreviewDepth
+	}
+	if opts.PreviewItems <= 0 {
+		opts.PreviewItems = DefaultPreviewItems
+	}
+
 	items := make([]Item, len(results))
 	for i, r := range results {
 		items[i] = Item{Result: r, Selected: false}
 	}
-	return Model{
+	nestItems(items)
+
+	m := Model{
 		Mode:   ModeList,
+		Roots:  opts.Roots,
 		Items:  items,
 		Cursor: 0,
+		Keys:   opts.Keys,
+		Help:   newHelp(),
+
+		Query:       opts.Query,
+		WalkOptions: opts.WalkOptions,
+		Scan:        opts.Scan,
+
+		SearchInput:  newSearchInput(),
+		ShowContent:  true,
+		PreviewDepth: opts.PreviewDepth,
+		PreviewItems: opts.PreviewItems,
+
+		Free:       opts.Free,
+		FreeOldest: opts.FreeOldest,
 	}
+	if opts.WalkOptions.Cache {
+		m.SizeCache = scan.NewSizeCache()
+	}
+	if m.Free > 0 {
+		m.loadDisks()
+		m.selectToFree()
+	}
+	return m
 }
 
 func (m Model) Init() tea.Cmd {
@@ -59,14 +142,79 @@ func (m Model) Init() tea.Cmd {
 }
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	next, cmd := m.handle(msg)
+	if nm, ok := next.(Model); ok {
+		nm.syncScroll()
+		contentCmd := nm.syncContent()
+		return nm, tea.Batch(cmd, contentCmd, nm.requestSizes(), nm.requestAges())
+	}
+	return next, cmd
+}
+
+func (m Model) handle(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.Width = msg.Width
 		m.Height = msg.Height
+		m.Help.Width = msg.Width
+		return m, nil
+	case dirNamesMsg:
+		if m.Prompt != nil {
+			prompt, cmd := m.Prompt.update(msg)
+			m.Prompt = &prompt
+			return m, cmd
+		}
+		return m, nil
+	case ageMsg:
+		m.applyAge(msg)
+		return m, nil
+	case contentMsg:
+		m.applyContent(msg)
+		return m, nil
+	case rescanMsg:
+		m.applyRescan(msg)
 		return m, nil
+	case execFinishedMsg:
+		m.applyExecFinished(msg)
+		return m, nil
+	case previewSearchMsg:
+		m.applySearch(msg)
+		return m, nil
+	case previewDeletedMsg:
+		m.applyPreviewDelete(msg)
+		return m, nil
+	case previewSizeMsg:
+		m.applySizes(msg)
+		return m, nil
+	case confirmSizeMsg:
+		if m.Confirm != nil {
+			m.Confirm.addMeasurement(msg.path, msg.measurement)
+		}
+		return m, nil
+	case tea.MouseMsg:
+		// The help overlay and the search prompt hide the list, so clicks
+		// must not reach it.
+		if m.ShowHelp || m.Prompt != nil {
+			return m, nil
+		}
+		return m.updateMouse(msg)
 	case tea.KeyMsg:
-		if m.Mode == ModePreview {
+		if m.ShowHelp {
+			m.ShowHelp = false
+			return m, nil
+		}
+		if m.Searching {
+			return m.updateSearchInput(msg)
+		}
+		if m.Prompt != nil {
+			return m.updatePrompt(msg)
+		}
+		m.Status = ""
+		switch m.Mode {
+		case ModePreview:
 			return m.updatePreview(msg)
+		case ModeConfirm:
+			return m.updateConfirm(msg)
 		}
 		return m.updateList(msg)
 	}
@@ -74,58 +222,54 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m Model) updateList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	key := msg.String()
+	k := m.Keys
+	prev := m.LastKey
+	m.LastKey = ""
 
-	switch key {
-	case "q", "esc", "ctrl+c":
+	switch {
+	case pressed(prev, msg, k.Quit):
 		m.Quitting = true
 		return m, tea.Quit
-	case "up", "k", "ctrl+p":
+	case pressed(prev, msg, k.Help):
+		m.ShowHelp = true
+	case pressed(prev, msg, k.Up):
 		m.MoveUp()
-		m.LastKey = ""
-	case "down", "j", "ctrl+n":
+	case pressed(prev, msg, k.Down):
 		m.MoveDown()
-		m.LastKey = ""
-	case "g":
-		if m.LastKey == "g" {
-			m.MoveToTop()
-			m.LastKey = ""
-		} else {
-			m.LastKey = "g"
-		}
-	case "G", "end":
-		m.MoveToBottom()
-		m.LastKey = ""
-	case "home":
+	case pressed(prev, msg, k.Top):
 		m.MoveToTop()
-		m.LastKey = ""
-	case " ":
+	case pressed(prev, msg, k.Bottom):
+		m.MoveToBottom()
+	case pressed(prev, msg, k.Toggle):
 		m.ToggleCurrent()
 		m.MoveDown()
-		m.LastKey = ""
-	case "a":
+	case pressed(prev, msg, k.SelectAll):
 		m.SelectAll()
-		m.LastKey = ""
-	case "A":
+	case pressed(prev, msg, k.DeselectAll):
 		m.DeselectAll()
-		m.LastKey = ""
-	case "i":
+	case pressed(prev, msg, k.Invert):
 		m.InvertSelection()
-		m.LastKey = ""
-	case "v", "l", "tab":
+	case pressed(prev, msg, k.Preview):
 		m.EnterPreview()
-		m.LastKey = ""
-	case "enter":
+	case pressed(prev, msg, k.Rescan):
+		return m, m.Rescan()
+	case pressed(prev, msg, k.NewSearch):
+		return m, m.OpenPrompt()
+	case pressed(prev, msg, k.Edit):
+		return m, m.OpenEditor()
+	case pressed(prev, msg, k.Page):
+		return m, m.OpenPager()
+	case pressed(prev, msg, k.Shell):
+		return m, m.OpenShell()
+	case pressed(prev, msg, k.Open):
+		return m, m.OpenExternal()
+	case pressed(prev, msg, k.Delete):
 		selected := m.GetSelectedPaths()
 		if len(selected) > 0 {
-			m.ToDelete = selected
-			m.DeleteCalled = true
-			m.Quitting = true
-			return m, tea.Quit
+			return m, m.EnterConfirm(selected)
 		}
-		m.LastKey = ""
-	default:
-		m.LastKey = ""
+	case startsSequence(msg, k.listBindings()...):
+		m.LastKey = msg.String()
 	}
 
 	return m, nil
@@ -135,33 +279,42 @@ func (m Model) View() string {
 	if m.Quitting {
 		return ""
 	}
-	if m.Mode == ModePreview {
+	if m.ShowHelp {
+		return m.viewHelp()
+	}
+	if m.Prompt != nil {
+		return m.viewPrompt()
+	}
+	switch m.Mode {
+	case ModePreview:
 		return m.viewPreview()
+	case ModeConfirm:
+		return m.viewConfirm()
 	}
 	return m.viewList()
 }
 
 func (m Model) viewList() string {
-	cwd, _ := os.Getwd()
-
-	title := fmt.Sprintf("Found %d folder(s)", len(m.Items))
+	title := fmt.Sprintf("Found %d %s", len(m.Items), m.itemNoun())
 	if count := m.SelectedCount(); count > 0 {
-		title = fmt.Sprintf("Found %d folder(s) • %d selected", len(m.Items), count)
+		title += fmt.Sprintf(" • %d selected", count)
+	}
+	if m.Free > 0 {
+		title += fmt.Sprintf(" • freeing %s of %s", formatSize(m.SelectedSize()), formatSize(m.Free))
+	}
+	if q := m.Query.String(); q != "" {
+		title += " • " + q
+	}
+	if m.Scanning {
+		title += " • rescanning…"
 	}
 
-	hint := "↑↓/jk move • space select • a all • v preview • enter delete • q quit"
+	hint := m.Help.ShortHelpView(listHelp{m.Keys}.ShortHelp())
 
 	var content strings.Builder
 
-	visibleHeight := m.Height - 6
-	if visibleHeight < 1 {
-		visibleHeight = 10
-	}
-
-	start := 0
-	if m.Cursor >= visibleHeight {
-		start = m.Cursor - visibleHeight + 1
-	}
+	visibleHeight := m.visibleHeight()
+	start := scrollOffset(m.ListOffset, m.Cursor, visibleHeight)
 	end := min(start+visibleHeight, len(m.Items))
 
 	for i := start; i < end; i++ {
@@ -177,18 +330,36 @@ func (m Model) viewList() string {
 			checkbox = Selected.Render("●")
 		}
 
-		relPath, err := filepath.Rel(cwd, item.Result.Path)
-		if err != nil {
-			relPath = item.Result.Path
+		relPath, label := m.displayPath(item.Result)
+		if item.Parent >= 0 {
+			parent := m.Items[item.Parent].Result.Path
+			if rel, err := filepath.Rel(parent, item.Result.Path); err == nil {
+				relPath = strings.Repeat("  ", item.Level-1) + "└ " + rel
+			}
+			label = ""
 		}
-
-		line := fmt.Sprintf("%s %s", checkbox, relPath)
+		line := fmt.Sprintf("%s %4s  %s", checkbox, formatAge(item.Result.Newest), relPath)
 		if item.Selected {
 			line = Selected.Render(line)
 		} else if i == m.Cursor {
 			line = Cursor.Render(line)
 		}
 
+		if item.Result.IsFile {
+			line += Dim.Render(fmt.Sprintf("  file, %s", formatSize(item.Result.Size)))
+		} else if item.Result.Size > 0 {
+			line += Dim.Render("  " + formatSize(item.Result.Size))
+		}
+		if r := item.Result; r.RealPath != "" && r.RealPath != r.Path {
+			line += Dim.Render(" → " + shortenHome(r.RealPath))
+		}
+		if label != "" {
+			line += Dim.Render("  " + label)
+		}
+		if item.Selected && m.selectedAncestor(i) >= 0 {
+			line += " " + Warning.Render("⚠ parent also selected")
+		}
+
 		content.WriteString(cursor)
 		content.WriteString(line)
 		content.WriteString("\n")
@@ -200,9 +371,43 @@ func (m Model) viewList() string {
 		content.WriteString("\n")
 	}
 
+	for _, line := range m.viewDisks() {
+		content.WriteString(line)
+		content.WriteString("\n")
+	}
+
+	if m.Status != "" {
+		content.WriteString(m.Status)
+		content.WriteString("\n")
+	}
+
 	return Title.Render(title) + "\n" + content.String() + Hint.Render(hint)
 }
 
+func (m Model) visibleHeight() int {
+	visibleHeight := m.Height - 6 - len(m.Disks)
+	if visibleHeight < 1 {
+		visibleHeight = 10
+	}
+	return visibleHeight
+}
+
+func scrollOffset(offset, cursor, visibleHeight int) int {
+	if cursor < offset {
+		return cursor
+	}
+	if cursor >= offset+visibleHeight {
+		return cursor - visibleHeight + 1
+	}
+	return offset
+}
+
+func (m *Model) syncScroll() {
+	visibleHeight := m.visibleHeight()
+	m.ListOffset = scrollOffset(m.ListOffset, m.Cursor, visibleHeight)
+	m.PreviewOffset = scrollOffset(m.PreviewOffset, m.PreviewCursor, visibleHeight)
+}
+
 func (m Model) SelectedCount() int {
 	count := 0
 	for _, item := range m.Items {
@@ -217,7 +422,7 @@ func (m Model) GetSelectedPaths() []string {
 	var paths []string
 	for _, item := range m.Items {
 		if item.Selected {
-			paths = append(paths, item.Result.Path)
+			paths = append(paths, item.Result.Target())
 		}
 	}
 	return paths
@@ -269,22 +474,53 @@ func (m *Model) MoveToBottom() {
 	}
 }
 
-func RunSelector(results []scan.Result) (Result, error) {
+func RunSelector(results []scan.Result, opts Options) (Result, error) {
 	if len(results) == 0 {
 		return Result{}, nil
 	}
 
-	model := NewModel(results)
+	model := NewModel(results, opts)
 
-	p := tea.NewProgram(model)
+	p := tea.NewProgram(model, tea.WithMouseCellMotion())
 	finalModel, err := p.Run()
 	if err != nil {
 		return Result{}, fmt.Errorf("error running TUI: %w", err)
 	}
 
 	m := finalModel.(Model)
+	m.SizeCache.Save()
 	return Result{
 		ToDelete:        m.ToDelete,
 		DeleteConfirmed: m.DeleteCalled,
 	}, nil
 }
+
+func nestItems(items []Item) {
+	var stack []int
+	for i := range items {
+		path := items[i].Result.Path
+		for len(stack) > 0 {
+			top := items[stack[len(stack)-1]].Result.Path
+			if strings.HasPrefix(path, top+string(filepath.Separator)) {
+				break
+			}
+			stack = stack[:len(stack)-1]
+		}
+
+		items[i].Parent = -1
+		if len(stack) > 0 {
+			items[i].Parent = stack[len(stack)-1]
+		}
+		items[i].Level = len(stack)
+		stack = append(stack, i)
+	}
+}
+
+func (m Model) selectedAncestor(i int) int {
+	for p := m.Items[i].Parent; p >= 0; p = m.Items[p].Parent {
+		if m.Items[p].Selected {
+			return p
+		}
+	}
+	return -1
+}
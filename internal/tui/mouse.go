@@ -0,0 +1,110 @@
+package tui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	listTopOffset     = 2
+	checkboxColumn    = 2
+	doubleClickWindow = 400 * time.Millisecond
+)
+
+func (m Model) updateMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	switch m.Mode {
+	case ModeList:
+		m.mouseList(msg)
+	case ModePreview:
+		m.mousePreview(msg)
+	}
+	return m, nil
+}
+
+func (m *Model) mouseList(msg tea.MouseMsg) {
+	switch msg.Button {
+	case tea.MouseButtonWheelUp:
+		m.MoveUp()
+		return
+	case tea.MouseButtonWheelDown:
+		m.MoveDown()
+		return
+	}
+
+	if msg.Action != tea.MouseActionPress || msg.Button != tea.MouseButtonLeft {
+		return
+	}
+
+	row, ok := m.rowAt(msg.Y, m.ListOffset, len(m.Items))
+	if !ok {
+		return
+	}
+
+	m.LastKey = ""
+	m.Cursor = row
+	if msg.X == checkboxColumn {
+		m.ToggleCurrent()
+		m.LastClickAt = time.Time{}
+		return
+	}
+
+	if m.isDoubleClick(row) {
+		m.EnterPreview()
+	}
+}
+
+func (m *Model) mousePreview(msg tea.MouseMsg) {
+	switch msg.Button {
+	case tea.MouseButtonWheelUp:
+		m.PreviewMoveUp()
+		return
+	case tea.MouseButtonWheelDown:
+		m.PreviewMoveDown()
+		return
+	}
+
+	if msg.Action != tea.MouseActionPress || msg.Button != tea.MouseButtonLeft {
+		return
+	}
+
+	row, ok := m.rowAt(msg.Y, m.PreviewOffset, len(m.PreviewNodes))
+	if !ok {
+		return
+	}
+
+	m.PreviewCursor = row
+	if msg.X == checkboxColumn {
+		m.ToggleMark()
+		m.LastClickAt = time.Time{}
+		return
+	}
+
+	if m.isDoubleClick(row) {
+		m.ToggleExpand()
+	}
+}
+
+func (m Model) rowAt(y, offset, count int) (int, bool) {
+	line := y - listTopOffset
+	if line < 0 || line >= m.visibleHeight() {
+		return 0, false
+	}
+	row := offset + line
+	if row >= count {
+		return 0, false
+	}
+	return row, true
+}
+
+func (m *Model) isDoubleClick(row int) bool {
+	now := time.Now()
+	double := row == m.LastClickRow && now.Sub(m.LastClickAt) <= doubleClickWindow
+	if double {
+		m.LastClickAt = time.Time{}
+	} else {
+		m.LastClickRow = row
+		m.LastClickAt = now
+	}
+	return double
+}
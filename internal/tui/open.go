@@ -0,0 +1,111 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type execFinishedMsg struct {
+	name string
+	err  error
+}
+
+func (m Model) highlightedPath() (string, bool) {
+	if m.Mode == ModePreview {
+		if m.PreviewCursor < len(m.PreviewNodes) {
+			node := m.PreviewNodes[m.PreviewCursor]
+			return node.Path, node.IsDir
+		}
+		return "", false
+	}
+	if m.Cursor < len(m.Items) {
+		r := m.Items[m.Cursor].Result
+		return r.Path, !r.IsFile
+	}
+	return "", false
+}
+
+func (m *Model) OpenEditor() tea.Cmd {
+	path, _ := m.highlightedPath()
+	if path == "" {
+		return nil
+	}
+	return m.runCommand(envCommand("EDITOR", "vi", path))
+}
+
+func (m *Model) OpenPager() tea.Cmd {
+	path, isDir := m.highlightedPath()
+	if path == "" {
+		return nil
+	}
+	if isDir {
+		m.Status = Error.Render(fmt.Sprintf("%s is a directory", filepath.Base(path)))
+		return nil
+	}
+	return m.runCommand(envCommand("PAGER", "less", path))
+}
+
+func (m *Model) OpenShell() tea.Cmd {
+	path, isDir := m.highlightedPath()
+	if path == "" {
+		return nil
+	}
+	if !isDir {
+		path = filepath.Dir(path)
+	}
+
+	shell := "/bin/sh"
+	if runtime.GOOS == "windows" {
+		shell = "cmd"
+	}
+	cmd := envCommand("SHELL", shell)
+	cmd.Dir = path
+	return m.runCommand(cmd)
+}
+
+func (m *Model) OpenExternal() tea.Cmd {
+	path, _ := m.highlightedPath()
+	if path == "" {
+		return nil
+	}
+
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", path)
+	case "windows":
+		cmd = exec.Command("cmd", "/c", "start", "", path)
+	default:
+		cmd = exec.Command("xdg-open", path)
+	}
+	return m.runCommand(cmd)
+}
+
+func envCommand(env, fallback string, args ...string) *exec.Cmd {
+	fields := strings.Fields(os.Getenv(env))
+	if len(fields) == 0 {
+		fields = []string{fallback}
+	}
+	return exec.Command(fields[0], append(fields[1:], args...)...)
+}
+
+func (m *Model) runCommand(cmd *exec.Cmd) tea.Cmd {
+	m.Status = ""
+	name := filepath.Base(cmd.Path)
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		return execFinishedMsg{name: name, err: err}
+	})
+}
+
+func (m *Model) applyExecFinished(msg execFinishedMsg) {
+	m.Content = nil
+	if msg.err != nil {
+		m.Status = Error.Render(fmt.Sprintf("%s: %v", msg.name, msg.err))
+	}
+}
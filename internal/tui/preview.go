@@ -2,66 +2,121 @@ package tui
 
 import (
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
 
 const (
-	maxPreviewDepth = 3
-	maxPreviewItems = 300
+	DefaultPreviewDepth = 3
+	DefaultPreviewItems = 300
 )
 
 type PreviewNode struct {
-	Name     string
-	Path     string
-	IsDir    bool
-	Expanded bool
-	Children []*PreviewNode
-	Depth    int
+	Name      string
+	Path      string
+	IsDir     bool
+	Expanded  bool
+	Children  []*PreviewNode
+	Parent    *PreviewNode
+	Depth     int
+	Size      int64
+	Sized     bool
+	Total     int
+	Truncated bool
+	Marked    bool
+
+	Mode        fs.FileMode
+	FileSize    int64
+	ModTime     time.Time
+	LinkTarget  string
+	LinkEscapes bool
+
+	realPath string
+	sizing   bool
 }
 
 func (m Model) updatePreview(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	key := msg.String()
+	k := m.Keys
+	prev := m.LastKey
+	m.LastKey = ""
 
-	switch key {
-	case "q", "esc":
+	switch {
+	case pressed(prev, msg, k.Back):
 		m.ExitPreview()
-	case "up", "k", "ctrl+p":
+	case pressed(prev, msg, k.Help):
+		m.ShowHelp = true
+	case pressed(prev, msg, k.Up):
 		m.PreviewMoveUp()
-	case "down", "j", "ctrl+n":
+	case pressed(prev, msg, k.Down):
 		m.PreviewMoveDown()
-	case "enter", "l":
+	case pressed(prev, msg, k.Top):
+		m.PreviewMoveToTop()
+	case pressed(prev, msg, k.Bottom):
+		m.PreviewMoveToBottom()
+	case pressed(prev, msg, k.Expand):
 		m.ToggleExpand()
-	case "h":
+	case pressed(prev, msg, k.Collapse):
 		if m.CollapseOrBack() {
 			m.ExitPreview()
 		}
-	case "n":
+	case pressed(prev, msg, k.NextFolder):
 		m.NextFolder()
-	case "p":
+	case pressed(prev, msg, k.PrevFolder):
 		m.PrevFolder()
+	case pressed(prev, msg, k.Contents):
+		m.ShowContent = !m.ShowContent
+	case pressed(prev, msg, k.Details):
+		m.ShowDetails = !m.ShowDetails
+	case pressed(prev, msg, k.Sort):
+		m.ToggleSort()
+	case pressed(prev, msg, k.Edit):
+		return m, m.OpenEditor()
+	case pressed(prev, msg, k.Page):
+		return m, m.OpenPager()
+	case pressed(prev, msg, k.Shell):
+		return m, m.OpenShell()
+	case pressed(prev, msg, k.Open):
+		return m, m.OpenExternal()
+	case pressed(prev, msg, k.Search):
+		return m, m.StartSearch()
+	case pressed(prev, msg, k.NextMatch):
+		m.NextMatch()
+	case pressed(prev, msg, k.PrevMatch):
+		m.PrevMatch()
+	case pressed(prev, msg, k.Mark):
+		m.ToggleMark()
+		m.PreviewMoveDown()
+	case pressed(prev, msg, k.DeleteMarked):
+		if nodes := m.MarkedNodes(); len(nodes) > 0 {
+			return m, m.EnterPreviewConfirm(nodes)
+		}
+	case pressed(prev, msg, k.LoadMore):
+		m.LoadMore()
+	case pressed(prev, msg, k.RaiseLimits):
+		m.RaiseLimits()
+	case startsSequence(msg, k.previewBindings()...):
+		m.LastKey = msg.String()
 	}
 
 	return m, nil
 }
 
 func (m Model) viewPreview() string {
-	cwd, _ := os.Getwd()
-
-	folderPath := ""
+	folderPath, label := "", ""
 	if len(m.Items) > 0 && m.Cursor < len(m.Items) {
-		relPath, err := filepath.Rel(cwd, m.Items[m.Cursor].Result.Path)
-		if err != nil {
-			relPath = m.Items[m.Cursor].Result.Path
-		}
-		folderPath = relPath
+		folderPath, label = m.displayPath(m.Items[m.Cursor].Result)
+	}
+	title := withLabel(fmt.Sprintf("Preview: %s (%d/%d)", folderPath, m.Cursor+1, len(m.Items)), label)
+	if m.PreviewTruncated {
+		title += Error.Render(fmt.Sprintf(" • truncated at %d items, depth %d", m.PreviewItems, m.PreviewDepth))
 	}
-	title := fmt.Sprintf("Preview: %s (%d/%d)", folderPath, m.Cursor+1, len(m.Items))
-	hint := "↑↓/jk move • enter/l expand • h back • n/p folder • q quit"
+	hint := m.Help.ShortHelpView(previewHelp{m.Keys}.ShortHelp())
 
 	var content strings.Builder
 
@@ -69,15 +124,8 @@ func (m Model) viewPreview() string {
 		content.WriteString(Dim.Render("  (empty)"))
 		content.WriteString("\n")
 	} else {
-		visibleHeight := m.Height - 6
-		if visibleHeight < 1 {
-			visibleHeight = 10
-		}
-
-		start := 0
-		if m.PreviewCursor >= visibleHeight {
-			start = m.PreviewCursor - visibleHeight + 1
-		}
+		visibleHeight := m.visibleHeight()
+		start := scrollOffset(m.PreviewOffset, m.PreviewCursor, visibleHeight)
 		end := min(start+visibleHeight, len(m.PreviewNodes))
 
 		for i := start; i < end; i++ {
@@ -97,21 +145,50 @@ func (m Model) viewPreview() string {
 					icon = "▼"
 				}
 				name := fmt.Sprintf("%s %s/", icon, node.Name)
-				if i == m.PreviewCursor {
+				switch {
+				case i == m.PreviewCursor:
 					line = Cursor.Render(indent + name)
-				} else {
+				case m.isMatch(node):
+					line = indent + Match.Render(name)
+				default:
 					line = indent + Dir.Render(name)
 				}
+				if node.Truncated {
+					line += truncatedMarker(node)
+				}
 			} else {
 				name := fmt.Sprintf("  %s", node.Name)
-				if i == m.PreviewCursor {
+				if node.LinkTarget != "" {
+					name = fmt.Sprintf("@ %s", node.Name)
+				}
+				switch {
+				case i == m.PreviewCursor:
 					line = Cursor.Render(indent + name)
-				} else {
+				case m.isMatch(node):
+					line = indent + Match.Render(name)
+				default:
 					line = indent + File.Render(name)
 				}
 			}
 
+			if node.LinkTarget != "" {
+				line += linkMarker(node)
+			}
+
+			mark := "○ "
+			if node.Marked {
+				mark = Selected.Render("● ")
+			} else if i == 0 {
+				mark = "  "
+			}
+
 			content.WriteString(cursor)
+			content.WriteString(mark)
+			if m.ShowDetails {
+				content.WriteString(detailsColumn(node))
+			} else {
+				content.WriteString(m.sizeColumn(node))
+			}
 			content.WriteString(line)
 			content.WriteString("\n")
 		}
@@ -123,10 +200,38 @@ func (m Model) viewPreview() string {
 		}
 	}
 
-	return Title.Render(title) + "\n" + content.String() + Hint.Render(hint)
+	body := content.String()
+	if m.showContentPane() {
+		leftWidth := m.Width / 2
+		rightWidth := m.Width - leftWidth - 3
+		tree := strings.Split(strings.TrimSuffix(body, "\n"), "\n")
+		body = joinColumns(tree, m.viewContent(rightWidth, m.visibleHeight()), leftWidth, rightWidth)
+	}
+
+	if status := m.viewPreviewStatus(); status != "" {
+		body += status + "\n"
+	}
+	if status := m.viewSearchStatus(); status != "" {
+		body += status + "\n"
+	}
+	if m.Status != "" {
+		body += m.Status + "\n"
+	}
+	if m.Searching {
+		return Title.Render(title) + "\n" + body + "\n" + m.SearchInput.View()
+	}
+
+	return Title.Render(title) + "\n" + body + Hint.Render(hint)
+}
+
+func truncatedMarker(node *PreviewNode) string {
+	if node.Total == 0 {
+		return Error.Render("  ⚠ truncated: not loaded")
+	}
+	return Error.Render(fmt.Sprintf("  ⚠ truncated: %d of %d shown", len(node.Children), node.Total))
 }
 
-func BuildPreviewTree(rootPath string) (*PreviewNode, bool) {
+func BuildPreviewTree(rootPath string, maxDepth, maxItems int) (*PreviewNode, bool) {
 	root := &PreviewNode{
 		Name:     filepath.Base(rootPath),
 		Path:     rootPath,
@@ -134,48 +239,49 @@ func BuildPreviewTree(rootPath string) (*PreviewNode, bool) {
 		Expanded: true,
 		Depth:    0,
 	}
+	root.realPath = rootPath
+	if resolved, err := filepath.EvalSymlinks(rootPath); err == nil {
+		root.realPath = resolved
+	}
+	if info, err := os.Lstat(rootPath); err == nil {
+		root.setInfo(info)
+		if !info.IsDir() {
+			root.IsDir = false
+			root.Expanded = false
+			root.Size, root.Sized = info.Size(), true
+			return root, false
+		}
+	}
 
 	itemCount := 1
 	truncated := false
 
 	var buildTree func(node *PreviewNode, depth int)
 	buildTree = func(node *PreviewNode, depth int) {
-		if depth >= maxPreviewDepth || itemCount >= maxPreviewItems {
+		if depth >= maxDepth || itemCount >= maxItems {
+			node.Truncated = true
 			truncated = true
 			return
 		}
 
-		entries, err := os.ReadDir(node.Path)
+		entries, err := readEntries(node.Path)
 		if err != nil {
 			return
 		}
-
-		sort.Slice(entries, func(i, j int) bool {
-			iDir := entries[i].IsDir()
-			jDir := entries[j].IsDir()
-			if iDir != jDir {
-				return iDir
-			}
-			return entries[i].Name() < entries[j].Name()
-		})
+		node.Total = len(entries)
 
 		for _, entry := range entries {
-			if itemCount >= maxPreviewItems {
+			if itemCount >= maxItems {
+				node.Truncated = true
 				truncated = true
 				return
 			}
 
-			child := &PreviewNode{
-				Name:     entry.Name(),
-				Path:     filepath.Join(node.Path, entry.Name()),
-				IsDir:    entry.IsDir(),
-				Expanded: false,
-				Depth:    depth + 1,
-			}
+			child := newPreviewNode(node, entry)
 			node.Children = append(node.Children, child)
 			itemCount++
 
-			if entry.IsDir() && depth < 1 {
+			if entry.IsDir() && depth+2 < maxDepth {
 				child.Expanded = true
 				buildTree(child, depth+1)
 			}
@@ -186,6 +292,79 @@ func BuildPreviewTree(rootPath string) (*PreviewNode, bool) {
 	return root, truncated
 }
 
+func readEntries(path string) ([]os.DirEntry, error) {
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		iDir := entries[i].IsDir()
+		jDir := entries[j].IsDir()
+		if iDir != jDir {
+			return iDir
+		}
+		return entries[i].Name() < entries[j].Name()
+	})
+	return entries, nil
+}
+
+func newPreviewNode(parent *PreviewNode, entry os.DirEntry) *PreviewNode {
+	node := &PreviewNode{
+		Name:     entry.Name(),
+		Path:     filepath.Join(parent.Path, entry.Name()),
+		IsDir:    entry.IsDir(),
+		Expanded: false,
+		Parent:   parent,
+		Depth:    parent.Depth + 1,
+	}
+	if info, err := entry.Info(); err == nil {
+		node.setInfo(info)
+	}
+	return node
+}
+
+func (n *PreviewNode) setInfo(info fs.FileInfo) {
+	n.Mode = info.Mode()
+	n.FileSize = info.Size()
+	n.ModTime = info.ModTime()
+	if n.Mode&fs.ModeSymlink != 0 {
+		n.LinkTarget, _ = os.Readlink(n.Path)
+		n.LinkEscapes = linkEscapes(n)
+	}
+}
+
+func (m *Model) loadChildren(node *PreviewNode, limit int) {
+	entries, err := readEntries(node.Path)
+	if err != nil {
+		return
+	}
+	node.Total = len(entries)
+
+	loaded := make(map[string]bool, len(node.Children))
+	for _, child := range node.Children {
+		loaded[child.Name] = true
+	}
+
+	added := 0
+	for _, entry := range entries {
+		if loaded[entry.Name()] {
+			continue
+		}
+		if added >= limit {
+			break
+		}
+		node.Children = append(node.Children, newPreviewNode(node, entry))
+		added++
+	}
+
+	node.Truncated = len(node.Children) < node.Total
+	if m.SortBySize {
+		sortBySize(node.Children)
+	} else {
+		sortByName(node.Children)
+	}
+}
+
 func FlattenPreviewTree(root *PreviewNode) []*PreviewNode {
 	if root == nil {
 		return nil
@@ -212,33 +391,56 @@ func (m *Model) ToggleExpand() {
 	node := m.PreviewNodes[m.PreviewCursor]
 	if node.IsDir {
 		if !node.Expanded && len(node.Children) == 0 {
-			entries, err := os.ReadDir(node.Path)
-			if err == nil {
-				sort.Slice(entries, func(i, j int) bool {
-					iDir := entries[i].IsDir()
-					jDir := entries[j].IsDir()
-					if iDir != jDir {
-						return iDir
-					}
-					return entries[i].Name() < entries[j].Name()
-				})
-				for _, entry := range entries {
-					child := &PreviewNode{
-						Name:     entry.Name(),
-						Path:     filepath.Join(node.Path, entry.Name()),
-						IsDir:    entry.IsDir(),
-						Expanded: false,
-						Depth:    node.Depth + 1,
-					}
-					node.Children = append(node.Children, child)
-				}
-			}
+			m.loadChildren(node, m.PreviewItems)
 		}
 		node.Expanded = !node.Expanded
 		m.PreviewNodes = FlattenPreviewTree(m.PreviewRoot)
 	}
 }
 
+func (m *Model) LoadMore() {
+	if m.PreviewCursor >= len(m.PreviewNodes) {
+		return
+	}
+	node := m.PreviewNodes[m.PreviewCursor]
+	if !node.IsDir && node.Parent != nil {
+		node = node.Parent
+	}
+	m.loadChildren(node, m.PreviewItems)
+	node.Expanded = true
+	m.reflowPreview()
+	m.PreviewTruncated = treeTruncated(m.PreviewRoot)
+}
+
+func (m *Model) RaiseLimits() {
+	m.PreviewDepth++
+	m.PreviewItems *= 2
+
+	var path string
+	if m.PreviewCursor < len(m.PreviewNodes) {
+		path = m.PreviewNodes[m.PreviewCursor].Path
+	}
+	m.EnterPreview()
+	for i, node := range m.PreviewNodes {
+		if node.Path == path {
+			m.PreviewCursor = i
+			break
+		}
+	}
+}
+
+func treeTruncated(node *PreviewNode) bool {
+	if node.Truncated {
+		return true
+	}
+	for _, child := range node.Children {
+		if treeTruncated(child) {
+			return true
+		}
+	}
+	return false
+}
+
 func (m *Model) CollapseOrBack() bool {
 	if m.PreviewCursor >= len(m.PreviewNodes) {
 		return true
@@ -273,6 +475,16 @@ func (m *Model) PreviewMoveDown() {
 	}
 }
 
+func (m *Model) PreviewMoveToTop() {
+	m.PreviewCursor = 0
+}
+
+func (m *Model) PreviewMoveToBottom() {
+	if len(m.PreviewNodes) > 0 {
+		m.PreviewCursor = len(m.PreviewNodes) - 1
+	}
+}
+
 func (m *Model) NextFolder() {
 	if m.Cursor < len(m.Items)-1 {
 		m.Cursor++
@@ -292,8 +504,17 @@ func (m *Model) EnterPreview() {
 		return
 	}
 	path := m.Items[m.Cursor].Result.Path
-	root, _ := BuildPreviewTree(path)
+	root, truncated := BuildPreviewTree(path, m.PreviewDepth, m.PreviewItems)
+	if !root.IsDir {
+		root.Size = m.Items[m.Cursor].Result.Size
+	}
+	if m.SortBySize {
+		sortTree(root)
+	}
 	m.PreviewRoot = root
+	m.PreviewTruncated = truncated
+	m.PreviewDeletion = nil
+	m.PreviewSearch = nil
 	m.PreviewNodes = FlattenPreviewTree(root)
 	m.PreviewCursor = 0
 	m.Mode = ModePreview
@@ -304,4 +525,7 @@ func (m *Model) ExitPreview() {
 	m.PreviewRoot = nil
 	m.PreviewNodes = nil
 	m.PreviewCursor = 0
+	m.PreviewTruncated = false
+	m.PreviewDeletion = nil
+	m.PreviewSearch = nil
 }
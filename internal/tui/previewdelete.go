@@ -0,0 +1,127 @@
+package tui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type PreviewDeletion struct {
+	Pending int
+	Deleted int
+	Freed   int64
+	Errors  []string
+}
+
+type previewDeletedMsg struct {
+	node *PreviewNode
+	err  error
+}
+
+func (m *Model) ToggleMark() {
+	if m.PreviewCursor == 0 || m.PreviewCursor >= len(m.PreviewNodes) {
+		return
+	}
+	node := m.PreviewNodes[m.PreviewCursor]
+	node.Marked = !node.Marked
+}
+
+func (m Model) MarkedNodes() []*PreviewNode {
+	var nodes []*PreviewNode
+	var walk func(node *PreviewNode)
+	walk = func(node *PreviewNode) {
+		if node.Marked {
+			nodes = append(nodes, node)
+			return
+		}
+		for _, child := range node.Children {
+			walk(child)
+		}
+	}
+	if m.PreviewRoot != nil {
+		walk(m.PreviewRoot)
+	}
+	return nodes
+}
+
+func (m *Model) DeleteMarked(nodes []*PreviewNode) tea.Cmd {
+	m.PreviewDeletion = &PreviewDeletion{Pending: len(nodes)}
+
+	cmds := make([]tea.Cmd, len(nodes))
+	for i, node := range nodes {
+		cmds[i] = deletePreviewNode(node)
+	}
+	return tea.Batch(cmds...)
+}
+
+func deletePreviewNode(node *PreviewNode) tea.Cmd {
+	path := node.Path
+	return func() tea.Msg {
+		return previewDeletedMsg{node: node, err: removePath(path)}
+	}
+}
+
+func (m *Model) applyPreviewDelete(msg previewDeletedMsg) {
+	node := msg.node
+	if d := m.PreviewDeletion; d != nil {
+		d.Pending--
+		if msg.err != nil {
+			d.Errors = append(d.Errors, fmt.Sprintf("%s: %v", node.Name, msg.err))
+		} else {
+			d.Deleted++
+			if node.Sized {
+				d.Freed += node.Size
+			}
+		}
+	}
+	if msg.err != nil {
+		return
+	}
+
+	parent := node.Parent
+	if parent == nil {
+		return
+	}
+	for i, child := range parent.Children {
+		if child == node {
+			parent.Children = append(parent.Children[:i:i], parent.Children[i+1:]...)
+			break
+		}
+	}
+	if parent.Total > 0 {
+		parent.Total--
+	}
+
+	for p := parent; p != nil; p = p.Parent {
+		if node.Sized && p.Sized {
+			p.Size = max(p.Size-node.Size, 0)
+		} else {
+			p.sizing = false
+			p.Sized = false
+		}
+	}
+
+	if root := m.PreviewRoot; root != nil && !root.Sized && !root.Truncated {
+		if total, ok := childrenSize(root); ok {
+			root.Size = total
+			root.Sized = true
+		}
+	}
+
+	m.reflowPreview()
+}
+
+func (m Model) viewPreviewStatus() string {
+	d := m.PreviewDeletion
+	if d == nil {
+		return ""
+	}
+	if d.Pending > 0 {
+		return Dim.Render(fmt.Sprintf("Deleting… %d remaining", d.Pending))
+	}
+	summary := fmt.Sprintf("Deleted %d item(s), freed %s", d.Deleted, formatSize(d.Freed))
+	if len(d.Errors) > 0 {
+		return Error.Render(fmt.Sprintf("%s • %d failed: %s", summary, len(d.Errors), d.Errors[0]))
+	}
+	return Success.Render(summary)
+}
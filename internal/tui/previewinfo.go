@@ -0,0 +1,58 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const detailsTimeFormat = "2006-01-02 15:04"
+
+func detailsColumn(node *PreviewNode) string {
+	size := "…"
+	switch {
+	case node.Sized:
+		size = formatSize(node.Size)
+	case !node.IsDir:
+		size = formatSize(node.FileSize)
+	}
+
+	mtime := strings.Repeat(" ", len(detailsTimeFormat))
+	if !node.ModTime.IsZero() {
+		mtime = node.ModTime.Format(detailsTimeFormat)
+	}
+
+	return Dim.Render(node.Mode.String()) + fmt.Sprintf(" %9s ", size) + Dim.Render(mtime) + " "
+}
+
+func linkMarker(node *PreviewNode) string {
+	marker := " -> " + node.LinkTarget
+	if node.LinkEscapes {
+		return Error.Render(marker + "  ⚠ outside folder")
+	}
+	return Dim.Render(marker)
+}
+
+// Resolved once when the node is loaded; View runs on every message and
+// node_modules/.bin alone can hold hundreds of links.
+func linkEscapes(node *PreviewNode) bool {
+	root := node
+	for root.Parent != nil {
+		root = root.Parent
+	}
+	if root == node {
+		return false
+	}
+
+	target := node.LinkTarget
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(filepath.Dir(node.Path), target)
+	}
+	if resolved, err := filepath.EvalSymlinks(target); err == nil {
+		target = resolved
+	}
+
+	rel, err := filepath.Rel(root.realPath, target)
+	return err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(os.PathSeparator))
+}
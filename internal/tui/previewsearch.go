@@ -0,0 +1,236 @@
+package tui
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const maxSearchMatches = 500
+
+type PreviewSearch struct {
+	Query     string
+	Matches   []string
+	Current   int
+	Running   bool
+	Truncated bool
+
+	matched map[string]bool
+}
+
+type previewSearchMsg struct {
+	root      string
+	query     string
+	matches   []string
+	truncated bool
+}
+
+func newSearchInput() textinput.Model {
+	ti := textinput.New()
+	ti.Prompt = "/"
+	ti.CharLimit = 256
+	ti.PromptStyle = lipgloss.NewStyle().Foreground(Accent)
+	ti.TextStyle = lipgloss.NewStyle().Foreground(Text)
+	return ti
+}
+
+func (m *Model) StartSearch() tea.Cmd {
+	m.Searching = true
+	m.SearchInput.SetValue("")
+	return m.SearchInput.Focus()
+}
+
+func (m Model) updateSearchInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter":
+		m.Searching = false
+		m.SearchInput.Blur()
+		query := strings.TrimSpace(m.SearchInput.Value())
+		if query == "" || m.PreviewRoot == nil {
+			m.PreviewSearch = nil
+			return m, nil
+		}
+		m.PreviewSearch = &PreviewSearch{Query: query, Running: true}
+		return m, searchTree(m.PreviewRoot.Path, query)
+	case "esc", "ctrl+c":
+		m.Searching = false
+		m.SearchInput.Blur()
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.SearchInput, cmd = m.SearchInput.Update(msg)
+	return m, cmd
+}
+
+func searchTree(root, query string) tea.Cmd {
+	return func() tea.Msg {
+		match := nameMatcher(query)
+		var matches []string
+		truncated := false
+
+		filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return nil
+			}
+			if path == root {
+				return nil
+			}
+			if match(d.Name()) {
+				if len(matches) >= maxSearchMatches {
+					truncated = true
+					return filepath.SkipAll
+				}
+				matches = append(matches, path)
+			}
+			return nil
+		})
+
+		return previewSearchMsg{root: root, query: query, matches: matches, truncated: truncated}
+	}
+}
+
+func nameMatcher(query string) func(string) bool {
+	if strings.ContainsAny(query, "*?[") {
+		return func(name string) bool {
+			ok, _ := filepath.Match(query, name)
+			return ok
+		}
+	}
+	query = strings.ToLower(query)
+	return func(name string) bool {
+		return strings.Contains(strings.ToLower(name), query)
+	}
+}
+
+func (m *Model) applySearch(msg previewSearchMsg) {
+	s := m.PreviewSearch
+	if s == nil || m.PreviewRoot == nil || msg.root != m.PreviewRoot.Path || msg.query != s.Query {
+		return
+	}
+
+	s.Running = false
+	s.Matches = msg.matches
+	s.Truncated = msg.truncated
+	s.Current = 0
+	s.matched = make(map[string]bool, len(msg.matches))
+	for _, path := range msg.matches {
+		s.matched[path] = true
+		m.revealPath(path)
+	}
+
+	m.PreviewNodes = FlattenPreviewTree(m.PreviewRoot)
+	m.jumpToMatch()
+}
+
+func (m *Model) revealPath(path string) {
+	rel, err := filepath.Rel(m.PreviewRoot.Path, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return
+	}
+
+	node := m.PreviewRoot
+	parts := strings.Split(rel, string(filepath.Separator))
+	for i, name := range parts {
+		if len(node.Children) == 0 {
+			m.loadChildren(node, m.PreviewItems)
+		}
+		child := findChild(node, name)
+		if child == nil {
+			child = m.addChild(node, name)
+			if child == nil {
+				return
+			}
+		}
+		if i < len(parts)-1 {
+			node.Expanded = true
+		}
+		node = child
+	}
+	if node.Parent != nil {
+		node.Parent.Expanded = true
+	}
+}
+
+func findChild(node *PreviewNode, name string) *PreviewNode {
+	for _, child := range node.Children {
+		if child.Name == name {
+			return child
+		}
+	}
+	return nil
+}
+
+func (m *Model) addChild(node *PreviewNode, name string) *PreviewNode {
+	info, err := os.Lstat(filepath.Join(node.Path, name))
+	if err != nil {
+		return nil
+	}
+	child := newPreviewNode(node, fs.FileInfoToDirEntry(info))
+	node.Children = append(node.Children, child)
+	node.Truncated = len(node.Children) < node.Total
+	if m.SortBySize {
+		sortBySize(node.Children)
+	} else {
+		sortByName(node.Children)
+	}
+	return child
+}
+
+func (m *Model) NextMatch() {
+	if s := m.PreviewSearch; s != nil && len(s.Matches) > 0 {
+		s.Current = (s.Current + 1) % len(s.Matches)
+		m.jumpToMatch()
+	}
+}
+
+func (m *Model) PrevMatch() {
+	if s := m.PreviewSearch; s != nil && len(s.Matches) > 0 {
+		s.Current = (s.Current - 1 + len(s.Matches)) % len(s.Matches)
+		m.jumpToMatch()
+	}
+}
+
+func (m *Model) jumpToMatch() {
+	s := m.PreviewSearch
+	if s == nil || s.Current >= len(s.Matches) {
+		return
+	}
+	path := s.Matches[s.Current]
+	m.revealPath(path)
+	m.PreviewNodes = FlattenPreviewTree(m.PreviewRoot)
+	for i, node := range m.PreviewNodes {
+		if node.Path == path {
+			m.PreviewCursor = i
+			return
+		}
+	}
+}
+
+func (m Model) isMatch(node *PreviewNode) bool {
+	return m.PreviewSearch != nil && m.PreviewSearch.matched[node.Path]
+}
+
+func (m Model) viewSearchStatus() string {
+	s := m.PreviewSearch
+	if s == nil {
+		return ""
+	}
+	if s.Running {
+		return Dim.Render(fmt.Sprintf("Searching for %q…", s.Query))
+	}
+	if len(s.Matches) == 0 {
+		return Error.Render(fmt.Sprintf("No matches for %q", s.Query))
+	}
+	status := fmt.Sprintf("Match %d/%d for %q", s.Current+1, len(s.Matches), s.Query)
+	if s.Truncated {
+		status += fmt.Sprintf(" (first %d)", maxSearchMatches)
+	}
+	return Selected.Render(status)
+}
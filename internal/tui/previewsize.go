@@ -0,0 +1,201 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/coeeter/zap/internal/scan"
+)
+
+const sizeBarWidth = 8
+
+var sizeSlots = make(chan struct{}, runtime.NumCPU())
+
+type previewSizeMsg struct {
+	nodes []*PreviewNode
+	sizes []int64
+}
+
+func (m *Model) requestSizes() tea.Cmd {
+	if m.Mode != ModePreview {
+		return nil
+	}
+
+	var cmds []tea.Cmd
+	if root := m.PreviewRoot; root != nil && root.Truncated && !root.sizing {
+		root.sizing = true
+		cmds = append(cmds, m.sizeNodes([]*PreviewNode{root}))
+	}
+
+	for _, node := range m.PreviewNodes {
+		if !node.IsDir || !node.Expanded {
+			continue
+		}
+
+		var files []*PreviewNode
+		for _, child := range node.Children {
+			if child.sizing {
+				continue
+			}
+			child.sizing = true
+			if child.IsDir {
+				cmds = append(cmds, m.sizeNodes([]*PreviewNode{child}))
+			} else {
+				files = append(files, child)
+			}
+		}
+		if len(files) > 0 {
+			cmds = append(cmds, m.sizeNodes(files))
+		}
+	}
+
+	return tea.Batch(cmds...)
+}
+
+func (m Model) sizeNodes(nodes []*PreviewNode) tea.Cmd {
+	sizes, apparent := m.SizeCache, m.WalkOptions.ApparentSize
+	root := rootOf(m.Roots, nodes[0].Path)
+	paths := make([]string, len(nodes))
+	dirs := make([]bool, len(nodes))
+	for i, n := range nodes {
+		paths[i] = n.Path
+		dirs[i] = n.IsDir
+	}
+
+	return func() tea.Msg {
+		sizeSlots <- struct{}{}
+		defer func() { <-sizeSlots }()
+
+		measured := make([]int64, len(paths))
+		for i, p := range paths {
+			if dirs[i] {
+				measured[i] = scan.NewSizer().Add(sizes.Measure(root, p)).Size(apparent)
+			} else if info, err := os.Lstat(p); err == nil {
+				measured[i] = scan.FileSize(info, apparent)
+			}
+		}
+		return previewSizeMsg{nodes: nodes, sizes: measured}
+	}
+}
+
+func (m *Model) applySizes(msg previewSizeMsg) {
+	parents := make(map[*PreviewNode]bool)
+	for i, node := range msg.nodes {
+		node.Size = msg.sizes[i]
+		node.Sized = true
+		if node.Parent != nil {
+			parents[node.Parent] = true
+		}
+	}
+
+	for parent := range parents {
+		if parent.Parent == nil && !parent.Truncated {
+			if total, ok := childrenSize(parent); ok {
+				parent.Size = total
+				parent.Sized = true
+			}
+		}
+		if m.SortBySize {
+			sortBySize(parent.Children)
+		}
+	}
+
+	if m.SortBySize {
+		m.reflowPreview()
+	}
+}
+
+func childrenSize(node *PreviewNode) (int64, bool) {
+	var total int64
+	for _, child := range node.Children {
+		if !child.Sized {
+			return 0, false
+		}
+		total += child.Size
+	}
+	return total, true
+}
+
+func (m *Model) ToggleSort() {
+	m.SortBySize = !m.SortBySize
+	if m.PreviewRoot == nil {
+		return
+	}
+	if m.SortBySize {
+		sortTree(m.PreviewRoot)
+	} else {
+		var byName func(node *PreviewNode)
+		byName = func(node *PreviewNode) {
+			sortByName(node.Children)
+			for _, child := range node.Children {
+				byName(child)
+			}
+		}
+		byName(m.PreviewRoot)
+	}
+	m.reflowPreview()
+}
+
+func (m *Model) reflowPreview() {
+	var current *PreviewNode
+	if m.PreviewCursor < len(m.PreviewNodes) {
+		current = m.PreviewNodes[m.PreviewCursor]
+	}
+	m.PreviewNodes = FlattenPreviewTree(m.PreviewRoot)
+	for i, node := range m.PreviewNodes {
+		if node == current {
+			m.PreviewCursor = i
+			return
+		}
+	}
+	m.PreviewCursor = min(m.PreviewCursor, max(len(m.PreviewNodes)-1, 0))
+}
+
+func sortTree(node *PreviewNode) {
+	sortBySize(node.Children)
+	for _, child := range node.Children {
+		sortTree(child)
+	}
+}
+
+func sortBySize(nodes []*PreviewNode) {
+	sort.SliceStable(nodes, func(i, j int) bool {
+		if nodes[i].Sized != nodes[j].Sized {
+			return nodes[i].Sized
+		}
+		if nodes[i].Size != nodes[j].Size {
+			return nodes[i].Size > nodes[j].Size
+		}
+		return nodes[i].Name < nodes[j].Name
+	})
+}
+
+func sortByName(nodes []*PreviewNode) {
+	sort.SliceStable(nodes, func(i, j int) bool {
+		if nodes[i].IsDir != nodes[j].IsDir {
+			return nodes[i].IsDir
+		}
+		return nodes[i].Name < nodes[j].Name
+	})
+}
+
+func (m Model) sizeColumn(node *PreviewNode) string {
+	if !node.Sized {
+		return Dim.Render(fmt.Sprintf("%9s %4s %s ", "…", "", strings.Repeat(" ", sizeBarWidth)))
+	}
+
+	size := fmt.Sprintf("%9s", formatSize(node.Size))
+	if node.Parent == nil || !node.Parent.Sized || node.Parent.Size == 0 {
+		return size + Dim.Render(fmt.Sprintf(" %4s %s ", "", strings.Repeat(" ", sizeBarWidth)))
+	}
+
+	ratio := float64(node.Size) / float64(node.Parent.Size)
+	filled := min(int(ratio*sizeBarWidth+0.5), sizeBarWidth)
+	bar := Selected.Render(strings.Repeat("█", filled)) + Dim.Render(strings.Repeat("░", sizeBarWidth-filled))
+
+	return size + Dim.Render(fmt.Sprintf(" %3.0f%%", ratio*100)) + " " + bar + " "
+}
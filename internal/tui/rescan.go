@@ -0,0 +1,113 @@
+package tui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/coeeter/zap/internal/history"
+	"github.com/coeeter/zap/internal/scan"
+)
+
+type rescanMsg struct {
+	results    []scan.Result
+	suggestion string
+	err        error
+}
+
+func (m *Model) Rescan() tea.Cmd {
+	if m.Scan == nil || m.Scanning {
+		return nil
+	}
+	m.Scanning = true
+	scanFn, roots, query, walkOpts := m.Scan, m.Roots, m.Query, m.WalkOptions
+	return func() tea.Msg {
+		results, err := scanFn(query)
+		msg := rescanMsg{results: results, err: err}
+		if err == nil && len(results) == 0 {
+			msg.suggestion = scan.Suggest(roots, query, walkOpts)
+		}
+		return msg
+	}
+}
+
+func (m *Model) OpenPrompt() tea.Cmd {
+	if m.Scan == nil || m.Scanning {
+		return nil
+	}
+	hist, _ := history.Load()
+	prompt := NewInputModel("node_modules", m.Roots, m.Query, hist)
+	m.Prompt = &prompt
+	return loadDirNames(m.Roots)
+}
+
+func (m Model) updatePrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	prompt, cmd := m.Prompt.update(msg)
+	if !prompt.quitting {
+		m.Prompt = &prompt
+		return m, cmd
+	}
+
+	m.Prompt = nil
+	if !prompt.submitted {
+		return m, nil
+	}
+	m.Query = prompt.Query()
+	return m, m.Rescan()
+}
+
+func (m Model) viewPrompt() string {
+	modal := Modal.Render(m.Prompt.view("esc cancel"))
+	if m.Width == 0 || m.Height == 0 {
+		return modal
+	}
+	return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, modal)
+}
+
+func (m *Model) applyRescan(msg rescanMsg) {
+	m.Scanning = false
+	if msg.err != nil {
+		m.Status = Error.Render(fmt.Sprintf("rescan failed: %v", msg.err))
+		return
+	}
+
+	selected := make(map[string]bool)
+	for _, item := range m.Items {
+		if item.Selected {
+			selected[item.Result.Path] = true
+		}
+	}
+
+	var current string
+	if m.Cursor < len(m.Items) {
+		current = m.Items[m.Cursor].Result.Path
+	}
+
+	before := len(m.Items)
+	items := make([]Item, len(msg.results))
+	cursor := -1
+	for i, r := range msg.results {
+		items[i] = Item{Result: r, Selected: selected[r.Path]}
+		if r.Path == current {
+			cursor = i
+		}
+	}
+	if cursor < 0 {
+		cursor = min(m.Cursor, max(len(items)-1, 0))
+	}
+
+	nestItems(items)
+	m.Items = items
+	m.Cursor = cursor
+	if m.Free > 0 {
+		m.loadDisks()
+	}
+	if len(items) > 0 {
+		_ = history.Add(m.Query.Name)
+	}
+	m.Status = Dim.Render(fmt.Sprintf("rescanned: %d %s, was %d", len(items), m.itemNoun(), before))
+	if msg.suggestion != "" {
+		m.Status = Error.Render(fmt.Sprintf("nothing named %s • did you mean %s? press %s to search again",
+			m.Query.Name, msg.suggestion, m.Keys.NewSearch.Help().Key))
+	}
+}
@@ -1,47 +1,176 @@
 package tui
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"fmt"
+	"os"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+type Theme struct {
+	Accent    lipgloss.TerminalColor
+	Highlight lipgloss.TerminalColor
+	Muted     lipgloss.TerminalColor
+	Text      lipgloss.TerminalColor
+	Positive  lipgloss.TerminalColor
+	Negative  lipgloss.TerminalColor
+	Mono      bool
+}
+
+var Themes = map[string]Theme{
+	"dark": {
+		Accent:    lipgloss.CompleteColor{TrueColor: "#ff87d7", ANSI256: "212", ANSI: "13"},
+		Highlight: lipgloss.CompleteColor{TrueColor: "#00afff", ANSI256: "39", ANSI: "14"},
+		Muted:     lipgloss.CompleteColor{TrueColor: "#585858", ANSI256: "240", ANSI: "8"},
+		Text:      lipgloss.CompleteColor{TrueColor: "#d0d0d0", ANSI256: "252", ANSI: "7"},
+		Positive:  lipgloss.CompleteColor{TrueColor: "#00d787", ANSI256: "42", ANSI: "10"},
+		Negative:  lipgloss.CompleteColor{TrueColor: "#ff0000", ANSI256: "196", ANSI: "9"},
+	},
+	"light": {
+		Accent:    lipgloss.CompleteColor{TrueColor: "#af005f", ANSI256: "125", ANSI: "5"},
+		Highlight: lipgloss.CompleteColor{TrueColor: "#005faf", ANSI256: "25", ANSI: "4"},
+		Muted:     lipgloss.CompleteColor{TrueColor: "#808080", ANSI256: "244", ANSI: "8"},
+		Text:      lipgloss.CompleteColor{TrueColor: "#262626", ANSI256: "235", ANSI: "0"},
+		Positive:  lipgloss.CompleteColor{TrueColor: "#008700", ANSI256: "28", ANSI: "2"},
+		Negative:  lipgloss.CompleteColor{TrueColor: "#d70000", ANSI256: "160", ANSI: "1"},
+	},
+	"high-contrast": {
+		Accent:    lipgloss.CompleteColor{TrueColor: "#ff00ff", ANSI256: "201", ANSI: "13"},
+		Highlight: lipgloss.CompleteColor{TrueColor: "#00ffff", ANSI256: "51", ANSI: "14"},
+		Muted:     lipgloss.CompleteColor{TrueColor: "#d0d0d0", ANSI256: "252", ANSI: "7"},
+		Text:      lipgloss.CompleteColor{TrueColor: "#ffffff", ANSI256: "231", ANSI: "15"},
+		Positive:  lipgloss.CompleteColor{TrueColor: "#00ff00", ANSI256: "46", ANSI: "10"},
+		Negative:  lipgloss.CompleteColor{TrueColor: "#ff5f5f", ANSI256: "203", ANSI: "9"},
+	},
+	"monochrome": {
+		Accent:    lipgloss.NoColor{},
+		Highlight: lipgloss.NoColor{},
+		Muted:     lipgloss.NoColor{},
+		Text:      lipgloss.NoColor{},
+		Positive:  lipgloss.NoColor{},
+		Negative:  lipgloss.NoColor{},
+		Mono:      true,
+	},
+}
 
 var (
-	Pink  = lipgloss.Color("212")
-	Cyan  = lipgloss.Color("39")
-	Gray  = lipgloss.Color("240")
-	White = lipgloss.Color("252")
-	Green = lipgloss.Color("42")
-	Red   = lipgloss.Color("196")
+	Accent    lipgloss.TerminalColor
+	Highlight lipgloss.TerminalColor
+	Muted     lipgloss.TerminalColor
+	Text      lipgloss.TerminalColor
+
+	Title    lipgloss.Style
+	Selected lipgloss.Style
+	Cursor   lipgloss.Style
+	Dim      lipgloss.Style
+	Hint     lipgloss.Style
+	Dir      lipgloss.Style
+	File     lipgloss.Style
+	Success  lipgloss.Style
+	Error    lipgloss.Style
+	Spinner  lipgloss.Style
+	Warning  lipgloss.Style
+	Modal    lipgloss.Style
+	Match    lipgloss.Style
+)
+
+func init() {
+	useTheme(Themes["dark"])
+}
+
+func ApplyTheme(name, colorMode string) error {
+	switch colorMode {
+	case "", "auto":
+		if os.Getenv("NO_COLOR") != "" {
+			lipgloss.SetColorProfile(termenv.Ascii)
+		}
+	case "always":
+		if lipgloss.ColorProfile() == termenv.Ascii {
+			lipgloss.SetColorProfile(termenv.ANSI256)
+		}
+	case "never":
+		lipgloss.SetColorProfile(termenv.Ascii)
+	default:
+		return fmt.Errorf("invalid color mode %q (want never, auto or always)", colorMode)
+	}
+
+	if name == "" || name == "auto" {
+		name = "dark"
+		if !lipgloss.HasDarkBackground() {
+			name = "light"
+		}
+	}
+
+	theme, ok := Themes[name]
+	if !ok {
+		return fmt.Errorf("unknown theme %q (want auto, dark, light, high-contrast or monochrome)", name)
+	}
+	if lipgloss.ColorProfile() == termenv.Ascii {
+		theme = Themes["monochrome"]
+	}
+
+	useTheme(theme)
+	return nil
+}
+
+func useTheme(t Theme) {
+	Accent = t.Accent
+	Highlight = t.Highlight
+	Muted = t.Muted
+	Text = t.Text
 
 	Title = lipgloss.NewStyle().
 		Bold(true).
-		Foreground(Pink).
+		Foreground(t.Accent).
 		MarginBottom(1)
 
 	Selected = lipgloss.NewStyle().
-			Foreground(Pink).
-			Bold(true)
+		Foreground(t.Accent).
+		Bold(true)
 
 	Cursor = lipgloss.NewStyle().
-		Foreground(Cyan)
+		Foreground(t.Highlight).
+		Reverse(t.Mono)
 
 	Dim = lipgloss.NewStyle().
-		Foreground(Gray)
+		Foreground(t.Muted).
+		Faint(t.Mono)
 
 	Hint = lipgloss.NewStyle().
-		Foreground(Gray).
+		Foreground(t.Muted).
+		Faint(t.Mono).
 		MarginTop(1)
 
 	Dir = lipgloss.NewStyle().
-		Foreground(Cyan).
+		Foreground(t.Highlight).
 		Bold(true)
 
 	File = lipgloss.NewStyle().
-		Foreground(White)
+		Foreground(t.Text)
 
 	Success = lipgloss.NewStyle().
-		Foreground(Green)
+		Foreground(t.Positive)
 
 	Error = lipgloss.NewStyle().
-		Foreground(Red)
+		Foreground(t.Negative).
+		Bold(t.Mono)
 
 	Spinner = lipgloss.NewStyle().
-		Foreground(Pink)
-)
+		Foreground(t.Accent)
+
+	Warning = lipgloss.NewStyle().
+		Foreground(t.Negative).
+		Bold(true).
+		Reverse(true)
+
+	Match = lipgloss.NewStyle().
+		Foreground(t.Accent).
+		Bold(true).
+		Underline(true)
+
+	Modal = lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(t.Accent).
+		Padding(1, 2)
+}